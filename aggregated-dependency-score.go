@@ -3,12 +3,80 @@ package aggregdepscore
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // transitiveTrustworthinessExponent is noted as "e" in the design paper
 const transitiveTrustworthinessExponent = 1.5
 
+// Parameters are the constants of the model (see the design paper).
+type Parameters struct {
+	// TransitiveTrustworthinessExponent is noted as "e"
+	TransitiveTrustworthinessExponent float64
+	// MinTrustworthiness is the trustworthiness of a score of 0
+	// with DefaultScoreTrustworthinessConverter
+	MinTrustworthiness float64
+	// TrustworthinessOffset is noted as "k"
+	TrustworthinessOffset float64
+}
+
+// ModelParameters returns the default constants used by evaluators
+// and by DefaultScoreTrustworthinessConverter (see WithParameters).
+func ModelParameters() Parameters {
+	return Parameters{
+		TransitiveTrustworthinessExponent: transitiveTrustworthinessExponent,
+		MinTrustworthiness:                minTrustworthiness,
+		TrustworthinessOffset:             trustworthinessOffset,
+	}
+}
+
+// Converter returns the DefaultScoreTrustworthinessConverter using p.
+func (p Parameters) Converter() *DefaultScoreTrustworthinessConverter {
+	return &DefaultScoreTrustworthinessConverter{
+		MinTrustworthiness:    p.MinTrustworthiness,
+		TrustworthinessOffset: p.TrustworthinessOffset,
+	}
+}
+
+// withDefaults replaces the zero fields of p with the default values
+func (p Parameters) withDefaults() Parameters {
+	defaults := ModelParameters()
+
+	if p.TransitiveTrustworthinessExponent == 0 {
+		p.TransitiveTrustworthinessExponent = defaults.TransitiveTrustworthinessExponent
+	}
+	if p.MinTrustworthiness == 0 {
+		p.MinTrustworthiness = defaults.MinTrustworthiness
+	}
+	if p.TrustworthinessOffset == 0 {
+		p.TrustworthinessOffset = defaults.TrustworthinessOffset
+	}
+
+	return p
+}
+
+func (p Parameters) validate() error {
+	if !(p.TransitiveTrustworthinessExponent > 0) || math.IsInf(p.TransitiveTrustworthinessExponent, 1) {
+		return fmt.Errorf("transitive trustworthiness exponent must be positive, got %g", p.TransitiveTrustworthinessExponent)
+	}
+
+	if !(p.MinTrustworthiness > 0 && p.MinTrustworthiness < 1) {
+		return fmt.Errorf("min trustworthiness must be between 0 and 1, got %g", p.MinTrustworthiness)
+	}
+
+	if !(p.TrustworthinessOffset > 1) || math.IsInf(p.TrustworthinessOffset, 1) {
+		return fmt.Errorf("trustworthiness offset must be greater than 1, got %g", p.TrustworthinessOffset)
+	}
+
+	return nil
+}
+
 type Package struct {
 	Ecosystem string
 	Name      string
@@ -24,16 +92,222 @@ func (p Package) String() string {
 	return fmt.Sprintf("%#v", p)
 }
 
+// LogValue implements slog.LogValuer
+// so that packages are logged as a group of attributes.
+func (p Package) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("ecosystem", p.Ecosystem),
+		slog.String("name", p.Name),
+		slog.String("version", p.Version),
+	)
+}
+
+// discardLogger is used when the user does not provide a logger
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func (e *Evaluator) EvaluateScore(ctx context.Context, p Package) (float64, error) {
-	aggregatedTrustworthiness, err := e.trustworthiness.evaluate(ctx, p, nil)
+	components, err := e.EvaluateTrustworthiness(ctx, p)
 	if err != nil {
 		return 0.0, err
 	}
 
-	return e.converter.ScoreFromTrustworthiness(aggregatedTrustworthiness), nil
+	return e.converter.ScoreFromTrustworthiness(components.Aggregated), nil
 }
 
-func NewEvaluator(intrinsic IntrinsicTrustworthinessEvaluator, deps DependencyResolver) (*Evaluator, error) {
+// EvaluateTrustworthiness returns the aggregated trustworthiness of p
+// along with its intrinsic and transitive components.
+// Unlike scores, trustworthiness values can be interpreted as probabilities
+// (see the design paper).
+func (e *Evaluator) EvaluateTrustworthiness(ctx context.Context, p Package) (TrustworthinessComponents, error) {
+	evaluation, err := e.Evaluate(ctx, p)
+	if err != nil {
+		return TrustworthinessComponents{}, err
+	}
+
+	return evaluation.Components, nil
+}
+
+// Evaluate is like EvaluateScore but returns details about the evaluation.
+func (e *Evaluator) Evaluate(ctx context.Context, p Package) (*Evaluation, error) {
+	return e.evaluate(ctx, &e.trustworthiness, p)
+}
+
+// evaluate evaluates root package p using the given trustworthiness evaluator,
+// which is either the one of e or a copy sharing a memo (see EvaluateScores)
+func (e *Evaluator) evaluate(ctx context.Context, trustworthiness *trustwhorthinessEvaluator, p Package) (result *Evaluation, err error) {
+	ctx, span := trustworthiness.tracer.Start(ctx, "aggregdepscore.Evaluate", trace.WithAttributes(packageAttributes(p)...))
+	defer func() {
+		if result != nil {
+			span.SetAttributes(
+				attribute.Float64("aggregdepscore.score", result.Score),
+				attribute.Int("aggregdepscore.nb_broken_cycles", len(result.BrokenCycles)),
+				attribute.Int("aggregdepscore.nb_fallbacks", len(result.Fallbacks)),
+				attribute.Int("aggregdepscore.nb_nodes", result.NbNodes),
+				attribute.Bool("aggregdepscore.truncated", result.Truncated),
+			)
+		}
+		endSpan(span, err)
+	}()
+
+	ctx = withRootPackage(ctx, p)
+	state := &evaluationState{buildTree: e.tree}
+
+	logTrustworthiness, err := trustworthiness.evaluateLog(ctx, state, p, nil)
+
+	if e.emitter != nil {
+		flushErr := e.emitter.Flush()
+		if flushErr != nil {
+			trustworthiness.log().WarnContext(ctx, "could not flush emitted scores", slog.String("error", flushErr.Error()))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	components := newTrustworthinessComponents(state.root.LogIntrinsic, state.root.LogTransitive)
+	aggregatedTrustworthiness := components.Aggregated
+	score := e.converter.ScoreFromTrustworthiness(aggregatedTrustworthiness)
+
+	if e.metrics != nil {
+		e.metrics.scores.Observe(score)
+	}
+
+	return &Evaluation{
+		Package:            p,
+		Score:              score,
+		Trustworthiness:    aggregatedTrustworthiness,
+		LogTrustworthiness: logTrustworthiness,
+		RankingScore:       rankingScore(score, logTrustworthiness, e.parameters.MinTrustworthiness),
+		Components:         components,
+		Tree:               state.tree,
+		DirectDependencies: e.directDependencies(state.directDependencies),
+		BrokenCycles:       state.cycles,
+		Fallbacks:          state.fallbacks,
+		NbNodes:            state.nbNodes,
+		Truncated:          len(state.truncations) > 0,
+		Truncations:        state.truncations,
+	}, nil
+}
+
+// EvaluatorOption configures the Evaluator created by NewEvaluator.
+type EvaluatorOption func(*evaluatorConfig)
+
+type evaluatorConfig struct {
+	trustDomainPolicy TrustDomainPolicy
+	repositories      RepositoryResolver
+	canonicalizer     Canonicalizer
+	logger            *slog.Logger
+	observers         multiObserver
+	fallback          *float64
+	tracerProvider    trace.TracerProvider
+	metrics           *Metrics
+	emitter           GaugeEmitter
+	limits            *Limits
+	concurrency       int
+	tree              bool
+	cacheTTL          time.Duration
+	parameters        Parameters
+}
+
+// WithParameters sets the constants of the model;
+// zero fields keep their default value (see ModelParameters).
+// Trustworthiness values of the same packages are only comparable
+// if they were evaluated with the same parameters.
+func WithParameters(p Parameters) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.parameters = p
+	}
+}
+
+// WithLimits bounds the depth, the number of nodes and the number of calls of evaluations;
+// what happens to the subtrees beyond the limits depends on limits.Policy.
+// Truncations are reported in Evaluation.Truncations.
+func WithLimits(limits Limits) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.limits = &limits
+	}
+}
+
+// WithScoreEmitter makes the evaluator emit, for every evaluated package,
+// its intrinsic and aggregated score as gauges (see NewDogStatsDEmitter).
+// The emitter is flushed at the end of every evaluation.
+func WithScoreEmitter(emitter GaugeEmitter) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.emitter = emitter
+	}
+}
+
+// WithMetrics makes the evaluator update the given Prometheus metrics.
+func WithMetrics(m *Metrics) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.metrics = m
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to trace evaluations;
+// by default the global tracer provider is used.
+func WithTracerProvider(tp trace.TracerProvider) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.tracerProvider = tp
+	}
+}
+
+// WithObserver adds an observer notified of the progress of evaluations;
+// it can be used several times to add several observers.
+func WithObserver(o Observer) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.observers = append(config.observers, o)
+	}
+}
+
+// WithIntrinsicFallback makes the evaluator assume the given intrinsic trustworthiness
+// for packages whose intrinsic trustworthiness cannot be evaluated
+// (for instance because they have no OSSF scorecard)
+// instead of failing the whole evaluation.
+// Fallbacks are reported in Evaluation.Fallbacks.
+// The trustworthiness must be greater than 0, since the logarithm of 0 is infinite, and at most 1.
+func WithIntrinsicFallback(trustworthiness float64) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.fallback = &trustworthiness
+	}
+}
+
+// WithLogger sets the logger used by the evaluator; by default nothing is logged.
+func WithLogger(logger *slog.Logger) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.logger = logger
+	}
+}
+
+// WithCanonicalizer sets how packages are identified when detecting dependency cycles;
+// by default a DefaultCanonicalizer ignoring versions is used.
+func WithCanonicalizer(c Canonicalizer) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.canonicalizer = c
+	}
+}
+
+// WithTrustDomainGrouping groups the direct dependencies of each package
+// by source repository and accounts for each group according to policy.
+//
+// The source repository of packages is obtained from the RepositoryResolver
+// set with WithRepositoryResolver, or else from intrinsic or deps if one of them
+// implements RepositoryResolver (which is the case of the deps.dev client).
+func WithTrustDomainGrouping(policy TrustDomainPolicy) EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.trustDomainPolicy = policy
+	}
+}
+
+// WithRepositoryResolver sets how the evaluator finds the source repository of packages.
+func WithRepositoryResolver(r RepositoryResolver) EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.repositories = r
+	}
+}
+
+func NewEvaluator(intrinsic IntrinsicTrustworthinessEvaluator, deps DependencyResolver, opts ...EvaluatorOption) (*Evaluator, error) {
 	if intrinsic == nil {
 		return nil, fmt.Errorf("intrinsic trustworthiness evaluator is required")
 	}
@@ -42,23 +316,228 @@ func NewEvaluator(intrinsic IntrinsicTrustworthinessEvaluator, deps DependencyRe
 		return nil, fmt.Errorf("dependency resolver is required")
 	}
 
-	return &Evaluator{
+	config := evaluatorConfig{
+		canonicalizer: &DefaultCanonicalizer{},
+		logger:        discardLogger,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	parameters := config.parameters.withDefaults()
+	err := parameters.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	if config.fallback != nil && !(*config.fallback > 0 && *config.fallback <= 1) {
+		return nil, fmt.Errorf("intrinsic fallback must be greater than 0 and at most 1, got %g", *config.fallback)
+	}
+
+	if config.limits != nil {
+		err := config.limits.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid limits: %w", err)
+		}
+	}
+
+	if config.metrics != nil {
+		config.observers = append(config.observers, &metricsObserver{metrics: config.metrics})
+	}
+
+	if config.emitter != nil {
+		config.observers = append(config.observers, &scoreEmittingObserver{
+			emitter:   config.emitter,
+			converter: parameters.Converter(),
+			logger:    config.logger,
+		})
+	}
+
+	if config.cacheTTL < 0 {
+		return nil, fmt.Errorf("cache TTL must be positive, got %s", config.cacheTTL)
+	}
+
+	if config.repositories == nil {
+		if r, ok := intrinsic.(RepositoryResolver); ok {
+			config.repositories = r
+		} else if r, ok := deps.(RepositoryResolver); ok {
+			config.repositories = r
+		}
+	}
+
+	evaluator := &Evaluator{
 		trustworthiness: trustwhorthinessEvaluator{
-			intrinsic: intrinsic,
-			deps:      deps,
+			intrinsic:     intrinsic,
+			deps:          deps,
+			canonicalizer: config.canonicalizer,
+			logger:        config.logger,
+			observer:      config.observers,
+			fallback:      config.fallback,
+			limits:        config.limits,
+			tracer:        defaultTracer(),
+			exponent:      parameters.TransitiveTrustworthinessExponent,
 		},
-		converter: &DefaultScoreTrustworthinessConverter{},
-	}, nil
+		converter:   parameters.Converter(),
+		parameters:  parameters,
+		metrics:     config.metrics,
+		emitter:     config.emitter,
+		concurrency: config.concurrency,
+		tree:        config.tree,
+	}
+
+	if config.cacheTTL > 0 {
+		cache := newEvaluationMemo(intrinsic, deps, config.metrics)
+		cache.ttl = config.cacheTTL
+		evaluator.trustworthiness.intrinsic = cache
+		evaluator.trustworthiness.deps = cache
+	}
+
+	if config.tracerProvider != nil {
+		evaluator.trustworthiness.tracer = config.tracerProvider.Tracer(instrumentationName)
+	}
+
+	if config.trustDomainPolicy != nil {
+		if config.repositories == nil {
+			return nil, fmt.Errorf("trust domain grouping requires a repository resolver")
+		}
+
+		evaluator.trustworthiness.grouping = &trustDomainGrouping{
+			policy:       config.trustDomainPolicy,
+			repositories: config.repositories,
+			cache:        make(map[Package]string),
+		}
+	}
+
+	return evaluator, nil
+}
+
+// Parameters returns the constants of the model used by e (see WithParameters).
+func (e *Evaluator) Parameters() Parameters {
+	return e.parameters
 }
 
 type Evaluator struct {
 	trustworthiness trustwhorthinessEvaluator
 	converter       ScoreTrustworthinessConverter
+	parameters      Parameters
+	metrics         *Metrics
+	emitter         GaugeEmitter
+	// concurrency is the number of root packages evaluated concurrently in batches
+	concurrency int
+	// tree tells if evaluations must record the tree of evaluated packages
+	tree bool
 }
 
 type trustwhorthinessEvaluator struct {
-	intrinsic IntrinsicTrustworthinessEvaluator
-	deps      DependencyResolver
+	intrinsic     IntrinsicTrustworthinessEvaluator
+	deps          DependencyResolver
+	canonicalizer Canonicalizer
+	logger        *slog.Logger
+	observer      Observer
+	tracer        trace.Tracer
+	// fallback is the intrinsic trustworthiness assumed when it cannot be evaluated,
+	// nil if evaluation errors must not be recovered from
+	fallback *float64
+	// grouping is nil unless dependencies are grouped by trust domain
+	grouping *trustDomainGrouping
+	// limits is nil if evaluations are not limited
+	limits *Limits
+	// exponent is the transitive trustworthiness exponent, noted "e" in the design paper;
+	// 0 for the default
+	exponent float64
+}
+
+// evaluationState holds what is collected during a single evaluation
+type evaluationState struct {
+	cycles      []Cycle
+	fallbacks   []Fallback
+	truncations []Truncation
+	// root holds the components of the trustworthiness of the root package
+	// once it is evaluated, without the linear values and LogAggregated
+	root TrustworthinessComponents
+	// nbNodes is the number of package evaluations so far
+	nbNodes int
+	// nbCalls is the number of calls to the intrinsic evaluator and dependency resolver so far,
+	// without memoized lookups
+	nbCalls int
+	// nbReserved is the number of nodes, and of calls, set aside for dependencies
+	// that are yet to be evaluated (see Limits.reserve)
+	nbReserved int
+	// directDependencies are the evaluated direct dependencies of the root package
+	directDependencies []evaluatedDependency
+	// buildTree tells if the tree of evaluated packages must be recorded in tree
+	buildTree bool
+	tree      *Node
+}
+
+// ancestor is a package on the path from the root to the package being evaluated
+type ancestor struct {
+	pkg Package
+	key Package
+	// node is nil unless the evaluation tree is recorded
+	node *Node
+}
+
+func (evaluator *trustwhorthinessEvaluator) canonicalize(p Package) Package {
+	if evaluator.canonicalizer == nil {
+		return (&DefaultCanonicalizer{}).Canonicalize(p)
+	}
+
+	return evaluator.canonicalizer.Canonicalize(p)
+}
+
+func (evaluator *trustwhorthinessEvaluator) transitiveExponent() float64 {
+	if evaluator.exponent == 0 {
+		return transitiveTrustworthinessExponent
+	}
+
+	return evaluator.exponent
+}
+
+func (evaluator *trustwhorthinessEvaluator) observe() Observer {
+	if evaluator.observer == nil {
+		return NopObserver{}
+	}
+
+	return evaluator.observer
+}
+
+func (evaluator *trustwhorthinessEvaluator) log() *slog.Logger {
+	if evaluator.logger == nil {
+		return discardLogger
+	}
+
+	return evaluator.logger
+}
+
+// nbCalls returns how many calls to the intrinsic evaluator and dependency resolver
+// fully evaluating p would take
+func (evaluator *trustwhorthinessEvaluator) nbCalls(p Package) int {
+	nbCalls := 0
+	if !intrinsicMemoized(evaluator.intrinsic, p) {
+		nbCalls++
+	}
+	if !dependenciesMemoized(evaluator.deps, p) {
+		nbCalls++
+	}
+
+	return nbCalls
+}
+
+// truncate records that the evaluation of p is truncated because of limit
+func (evaluator *trustwhorthinessEvaluator) truncate(ctx context.Context, state *evaluationState, p Package, depth int, limit string, node *Node) {
+	policy := evaluator.limits.Policy
+	evaluator.log().InfoContext(ctx, "truncating evaluation",
+		slog.Any("package", p),
+		slog.Int("depth", depth),
+		slog.String("limit", limit),
+		slog.String("policy", policy.String()),
+	)
+	truncation := Truncation{Package: p, Depth: depth, Limit: limit, Policy: policy}
+	state.truncations = append(state.truncations, truncation)
+	if node != nil {
+		node.Truncation = &truncation
+	}
 }
 
 type IntrinsicTrustworthinessEvaluator interface {
@@ -69,45 +548,245 @@ type DependencyResolver interface {
 	GetDirectDependencies(ctx context.Context, p Package) ([]Package, error)
 }
 
-func (evaluator *trustwhorthinessEvaluator) evaluate(ctx context.Context, p Package, ancestors map[string]struct{}) (float64, error) {
-	intrinsic, err := evaluator.intrinsic.EvaluateIntrinsicTrustworthiness(ctx, p)
+// evaluate returns the aggregated trustworthiness of p, noted T'(p) in the design paper
+func (evaluator *trustwhorthinessEvaluator) evaluate(ctx context.Context, state *evaluationState, p Package, ancestors []ancestor) (float64, error) {
+	logResult, err := evaluator.evaluateLog(ctx, state, p, ancestors)
 	if err != nil {
-		return 0.0, fmt.Errorf("evaluating intrinsic trustworthiness of package: %w", err)
+		return 0.0, err
 	}
 
-	result := intrinsic
+	return math.Exp(logResult), nil
+}
 
-	deps, err := evaluator.deps.GetDirectDependencies(ctx, p)
+// evaluateLog returns the natural logarithm of the aggregated trustworthiness of p.
+//
+// Computations are done in log space because the aggregated trustworthiness
+// of packages with huge dependency graphs is the product of so many factors
+// that it would underflow to zero, making such packages impossible to compare.
+func (evaluator *trustwhorthinessEvaluator) evaluateLog(ctx context.Context, state *evaluationState, p Package, ancestors []ancestor) (float64, error) {
+	logIntrinsic, logTransitive, err := evaluator.evaluateLogComponents(ctx, state, p, ancestors)
 	if err != nil {
-		return 0.0, fmt.Errorf("getting direct dependencies of package: %w", err)
+		return 0.0, err
 	}
 
+	return logIntrinsic + logTransitive, nil
+}
+
+// evaluateLogComponents is like evaluateLog but returns separately
+// the logarithms of the intrinsic trustworthiness of p and of the product over its dependencies
+func (evaluator *trustwhorthinessEvaluator) evaluateLogComponents(ctx context.Context, state *evaluationState, p Package, ancestors []ancestor) (logIntrinsic float64, logTransitive float64, err error) {
+	if evaluator.tracer != nil {
+		var span trace.Span
+		ctx, span = evaluator.tracer.Start(ctx, "aggregdepscore.evaluate", trace.WithAttributes(packageAttributes(p)...))
+		span.SetAttributes(attribute.Int("aggregdepscore.depth", len(ancestors)))
+		defer func() {
+			if err == nil {
+				result := logIntrinsic + logTransitive
+				span.SetAttributes(
+					attribute.Float64("aggregdepscore.aggregated_trustworthiness", math.Exp(result)),
+					attribute.Float64("aggregdepscore.log_aggregated_trustworthiness", result),
+				)
+			}
+			endSpan(span, err)
+		}()
+	}
+
+	logger := evaluator.log()
+	observer := evaluator.observe()
+	depth := len(ancestors)
+	start := time.Now()
+
+	var node *Node
+	if state.buildTree {
+		node = &Node{Package: p, Depth: depth, exponent: evaluator.exponent}
+		if depth == 0 {
+			state.tree = node
+		} else {
+			parent := ancestors[depth-1].node
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	// whether the dependencies of the package must be evaluated
+	descend := true
+
+	if limit, value := evaluator.limits.exceeded(state, depth, evaluator.nbCalls(p)); limit != "" {
+		policy := evaluator.limits.Policy
+		if policy == TruncateFail {
+			return 0.0, 0.0, &ErrLimitExceeded{Limit: limit, Value: value, Package: p}
+		}
+
+		evaluator.truncate(ctx, state, p, depth, limit, node)
+
+		if policy == TruncateAssumeTrustworthiness {
+			// the assumed trustworthiness is accounted for as intrinsic
+			result := math.Log(evaluator.limits.AssumedTrustworthiness)
+			if depth == 0 {
+				state.root = TrustworthinessComponents{LogIntrinsic: result}
+			}
+			if node != nil {
+				node.setAggregated(result)
+			}
+			return result, 0.0, nil
+		}
+
+		descend = false
+	}
+
+	state.nbNodes++
+
+	logger.DebugContext(ctx, "evaluating package", slog.Any("package", p), slog.Int("depth", depth))
+	observer.NodeStarted(ctx, NodeStartedEvent{Package: p, Depth: depth})
+
+	intrinsic, called, err := lookupIntrinsic(ctx, evaluator.intrinsic, p)
+	if called {
+		state.nbCalls++
+	}
+	if err != nil {
+		observer.Error(ctx, ErrorEvent{Package: p, Depth: depth, Source: ErrorSourceIntrinsicEvaluator, Err: err})
+
+		if evaluator.fallback == nil {
+			return 0.0, 0.0, fmt.Errorf("evaluating intrinsic trustworthiness of package: %w", err)
+		}
+
+		intrinsic = *evaluator.fallback
+
+		fallback := Fallback{Package: p, IntrinsicTrustworthiness: intrinsic, Reason: err.Error()}
+		logger.WarnContext(ctx, "assuming intrinsic trustworthiness",
+			slog.Any("package", p),
+			slog.Float64("intrinsic_trustworthiness", intrinsic),
+			slog.String("reason", fallback.Reason),
+		)
+		state.fallbacks = append(state.fallbacks, fallback)
+		observer.FallbackApplied(ctx, fallback)
+		if node != nil {
+			node.Fallback = &fallback
+		}
+	}
+
+	if node != nil {
+		node.IntrinsicTrustworthiness = intrinsic
+	}
+
+	var deps []Package
+	if descend {
+		deps, called, err = lookupDependencies(ctx, evaluator.deps, p)
+		if called {
+			state.nbCalls++
+		}
+		if err != nil {
+			observer.Error(ctx, ErrorEvent{Package: p, Depth: depth, Source: ErrorSourceDependencyResolver, Err: err})
+			return 0.0, 0.0, fmt.Errorf("getting direct dependencies of package: %w", err)
+		}
+
+		if limit, _ := evaluator.limits.reserve(state, len(deps)); limit != "" {
+			evaluator.truncate(ctx, state, p, depth, limit, node)
+			deps = nil
+		}
+	}
+
+	// copy ancestors to avoid modifying the original slice;
+	// one day we may want to run the algorithm in parallel
+	// so we will need to be careful with shared state
+	path := make([]ancestor, len(ancestors), len(ancestors)+1)
+	copy(path, ancestors)
+	path = append(path, ancestor{pkg: p, key: evaluator.canonicalize(p), node: node})
+
+	var evaluated []evaluatedDependency
+
 	for _, dep := range deps {
-		// XXX sometimes different names can refer to the same package,
-		// for instance with gopkg.in URLs;
-		// XXX should we consider the version as well?
-		if _, ok := ancestors[dep.Name]; ok {
+		evaluator.limits.release(state)
+
+		// packages are compared using their canonical identity
+		// because different names can refer to the same package,
+		// for instance with gopkg.in URLs
+		if cycle, ok := findCycle(path, dep, evaluator.canonicalize(dep)); ok {
 			// depedency cycle (see TestCycleHandling)
-			// TODO (https://github.com/DataDog/aggregated-dependency-score/issues/19) emit a log
+			logger.InfoContext(ctx, "ignoring dependency cycle",
+				slog.Any("package", p),
+				slog.Any("dependency", dep),
+				slog.Int("cycle_length", len(cycle.Packages)-1),
+			)
+			state.cycles = append(state.cycles, cycle)
+			observer.CycleSkipped(ctx, cycle)
+			if node != nil {
+				node.Children = append(node.Children, newCycleCutoffNode(cycle, depth+1))
+			}
 			continue
 		}
 
-		// copy ancestors to avoid modifying the original map;
-		// one day we may want to run the algorithm in parallel
-		// so we will need to be careful with shared state
-		childAncestors := make(map[string]struct{})
-		for k, v := range ancestors {
-			childAncestors[k] = v
+		logTQ, logTransitiveQ, err := evaluator.evaluateLogComponents(ctx, state, dep, path)
+		if err != nil {
+			return 0.0, 0.0, fmt.Errorf("evaluating aggregated trustworthiness of %s: %w", dep, err)
 		}
-		childAncestors[p.Name] = struct{}{}
 
-		tPrimeQ, err := evaluator.evaluate(ctx, dep, childAncestors)
+		evaluated = append(evaluated, evaluatedDependency{pkg: dep, logIntrinsic: logTQ, logAggregated: logTQ + logTransitiveQ})
+	}
+
+	// logarithm of the product of the T'(q)^e
+	logTransitive = 0.0
+
+	if evaluator.grouping != nil {
+		domains, err := evaluator.grouping.combine(ctx, evaluated)
 		if err != nil {
-			return 0.0, fmt.Errorf("evaluating aggregated trustworthiness of %s: %w", dep, err)
+			return 0.0, 0.0, fmt.Errorf("grouping dependencies by trust domain: %w", err)
+		}
+
+		for _, logTPrimeD := range domains {
+			logTransitive += evaluator.transitiveExponent() * logTPrimeD
+		}
+	} else {
+		for _, dep := range evaluated {
+			logTransitive += evaluator.transitiveExponent() * dep.logAggregated
+		}
+	}
+
+	logIntrinsic = math.Log(intrinsic)
+	result := logIntrinsic + logTransitive
+
+	if depth == 0 {
+		state.root = TrustworthinessComponents{LogIntrinsic: math.Log(intrinsic), LogTransitive: logTransitive}
+		state.directDependencies = evaluated
+	}
+
+	if node != nil {
+		node.setAggregated(result)
+	}
+
+	logger.DebugContext(ctx, "evaluated package",
+		slog.Any("package", p),
+		slog.Float64("intrinsic_trustworthiness", intrinsic),
+		slog.Float64("aggregated_trustworthiness", math.Exp(result)),
+		slog.Float64("log_aggregated_trustworthiness", result),
+		slog.Int("nb_dependencies", len(evaluated)),
+	)
+	observer.NodeFinished(ctx, NodeFinishedEvent{
+		Package:                   p,
+		Depth:                     depth,
+		IntrinsicTrustworthiness:  intrinsic,
+		AggregatedTrustworthiness: math.Exp(result),
+		Duration:                  time.Since(start),
+	})
+
+	return logIntrinsic, logTransitive, nil
+}
+
+// findCycle tells if dep (whose canonical identity is key) is already in path,
+// and if so returns the corresponding cycle
+func findCycle(path []ancestor, dep Package, key Package) (Cycle, bool) {
+	for i, a := range path {
+		if a.key != key {
+			continue
+		}
+
+		cycle := Cycle{Packages: make([]Package, 0, len(path)-i+1)}
+		for _, each := range path[i:] {
+			cycle.Packages = append(cycle.Packages, each.pkg)
 		}
+		cycle.Packages = append(cycle.Packages, dep)
 
-		result *= math.Pow(tPrimeQ, transitiveTrustworthinessExponent)
+		return cycle, true
 	}
 
-	return result, nil
+	return Cycle{}, false
 }
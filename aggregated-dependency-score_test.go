@@ -1,10 +1,14 @@
 package aggregdepscore
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -42,9 +46,12 @@ func (eval *testIntrinsicTrustworthinessEvaluator) EvaluateIntrinsicTrustworthin
 
 type testDependencyResolver struct {
 	directDependencyNamesByName map[string][]string
+	nbQueries                   int
 }
 
 func (r *testDependencyResolver) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	r.nbQueries++
+
 	names, ok := r.directDependencyNamesByName[p.Name]
 	if !ok {
 		return nil, fmt.Errorf("unknown package %q", p.Name)
@@ -73,7 +80,7 @@ func TestAggregatedTrustworthinessEvaluation(t *testing.T) {
 			},
 		}
 
-		tPrimeA, err := eval.evaluate(context.Background(), Package{Name: "A"}, nil)
+		tPrimeA, err := eval.evaluate(context.Background(), &evaluationState{}, Package{Name: "A"}, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -115,7 +122,7 @@ func TestAggregatedTrustworthinessEvaluation(t *testing.T) {
 			},
 		}
 
-		tPrimeA, err := eval.evaluate(context.Background(), Package{Name: "A"}, nil)
+		tPrimeA, err := eval.evaluate(context.Background(), &evaluationState{}, Package{Name: "A"}, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -153,7 +160,7 @@ func TestCycleHandling(t *testing.T) {
 		},
 	}
 
-	firstScore, err := evaluator.evaluate(context.Background(), Package{Name: "A"}, nil)
+	firstScore, err := evaluator.evaluate(context.Background(), &evaluationState{}, Package{Name: "A"}, nil)
 	if err != nil {
 		var tooManyQueriesErr *ErrTooManyQueries
 		if errors.As(err, &tooManyQueriesErr) {
@@ -184,7 +191,7 @@ func TestCycleHandling(t *testing.T) {
 		},
 	}
 
-	secondScore, err := evaluator.evaluate(context.Background(), Package{Name: "A"}, nil)
+	secondScore, err := evaluator.evaluate(context.Background(), &evaluationState{}, Package{Name: "A"}, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -196,3 +203,358 @@ func TestCycleHandling(t *testing.T) {
 		)
 	}
 }
+
+// testPackageDependencyResolver is like testDependencyResolver
+// but with full package identities (ecosystem, name and version)
+type testPackageDependencyResolver struct {
+	directDependenciesByPackage map[Package][]Package
+}
+
+func (r *testPackageDependencyResolver) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	deps, ok := r.directDependenciesByPackage[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown package %v", p)
+	}
+
+	return deps, nil
+}
+
+func TestCycleReporting(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{
+				"A": 0.92,
+				"B": 0.94,
+				"C": 0.93,
+			},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{
+				"A": {"B"},
+				"B": {"C"},
+				"C": {"A", "B"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"A>B>C>A", "B>C>B"}
+
+	if len(evaluation.BrokenCycles) != len(expected) {
+		t.Fatalf("expected %d cycles, got %+v", len(expected), evaluation.BrokenCycles)
+	}
+
+	for i, cycle := range evaluation.BrokenCycles {
+		var names []string
+		for _, p := range cycle.Packages {
+			names = append(names, p.Name)
+		}
+
+		if strings.Join(names, ">") != expected[i] {
+			t.Errorf("expected cycle %s, got %s", expected[i], strings.Join(names, ">"))
+		}
+	}
+}
+
+func TestCycleDetectionUsesCanonicalIdentity(t *testing.T) {
+	intrinsic := &testIntrinsicTrustworthinessEvaluator{
+		trustworthinessByName: map[string]float64{
+			"gopkg.in/yaml.v3":           0.92,
+			"github.com/go-yaml/yaml/v3": 0.92,
+			"six":                        0.94,
+		},
+		maxQueryNumber: 1,
+	}
+
+	yamlAlias := Package{Ecosystem: "go", Name: "gopkg.in/yaml.v3", Version: "v3.0.1"}
+	yaml := Package{Ecosystem: "go", Name: "github.com/go-yaml/yaml/v3", Version: "v3.0.1"}
+	npmSix := Package{Ecosystem: "npm", Name: "six", Version: "1.0.0"}
+	pypiSix := Package{Ecosystem: "pypi", Name: "six", Version: "1.16.0"}
+
+	evaluator, err := NewEvaluator(intrinsic, &testPackageDependencyResolver{
+		directDependenciesByPackage: map[Package][]Package{
+			// an alias of the same package is a cycle
+			yamlAlias: {yaml},
+			yaml:      {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), yamlAlias)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(evaluation.BrokenCycles) != 1 {
+		t.Fatalf("expected gopkg.in alias to be detected as a cycle, got %+v", evaluation.BrokenCycles)
+	}
+
+	// packages with the same name in different ecosystems are not a cycle
+	intrinsic.maxQueryNumber = 0
+	evaluator, err = NewEvaluator(intrinsic, &testPackageDependencyResolver{
+		directDependenciesByPackage: map[Package][]Package{
+			npmSix:  {pypiSix},
+			pypiSix: {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err = evaluator.Evaluate(context.Background(), npmSix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(evaluation.BrokenCycles) != 0 {
+		t.Fatalf("expected no cycle, got %+v", evaluation.BrokenCycles)
+	}
+
+	if math.Abs(evaluation.Trustworthiness-0.94*math.Pow(0.94, transitiveTrustworthinessExponent)) > 1e-12 {
+		t.Fatalf("dependency in another ecosystem was not accounted for: got %g", evaluation.Trustworthiness)
+	}
+}
+
+func TestCycleLogging(t *testing.T) {
+	var logs bytes.Buffer
+
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.92, "B": 0.94},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"B"}, "B": {"A"}},
+		},
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var record struct {
+		Level      string
+		Msg        string
+		Package    Package
+		Dependency Package
+	}
+
+	err = json.Unmarshal(logs.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("expected exactly one JSON log record, got %q: %v", logs.String(), err)
+	}
+
+	if record.Level != "INFO" || record.Msg != "ignoring dependency cycle" {
+		t.Errorf("unexpected log record: %+v", record)
+	}
+
+	if record.Package.Name != "B" || record.Dependency.Name != "A" {
+		t.Errorf("unexpected package attributes in log record: %+v", record)
+	}
+}
+
+func TestLogSpaceAccumulation(t *testing.T) {
+	// wide returns the evaluation of a package with n direct dependencies;
+	// with thousands of dependencies the aggregated trustworthiness underflows
+	wide := func(n int) *Evaluation {
+		trustworthinessByName := map[string]float64{"root": 0.9}
+		directDependencyNamesByName := map[string][]string{"root": {}}
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("dep-%d", i)
+			trustworthinessByName[name] = 0.5
+			directDependencyNamesByName[name] = []string{}
+			directDependencyNamesByName["root"] = append(directDependencyNamesByName["root"], name)
+		}
+
+		evaluator, err := NewEvaluator(
+			&testIntrinsicTrustworthinessEvaluator{trustworthinessByName: trustworthinessByName},
+			&testDependencyResolver{directDependencyNamesByName: directDependencyNamesByName},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "root"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return evaluation
+	}
+
+	bad := wide(1000)
+	catastrophic := wide(2000)
+
+	if bad.Trustworthiness != 0 || catastrophic.Trustworthiness != 0 {
+		t.Fatalf("expected trustworthiness to underflow, got %g and %g", bad.Trustworthiness, catastrophic.Trustworthiness)
+	}
+
+	expected := math.Log(0.9) + 2000*transitiveTrustworthinessExponent*math.Log(0.5)
+	if math.Abs(catastrophic.LogTrustworthiness-expected) > 1e-9 {
+		t.Errorf("expected log trustworthiness %f, got %f", expected, catastrophic.LogTrustworthiness)
+	}
+
+	if bad.Score != 0 || catastrophic.Score != 0 {
+		t.Errorf("expected scores to be clamped at zero, got %g and %g", bad.Score, catastrophic.Score)
+	}
+
+	if !(catastrophic.RankingScore < bad.RankingScore && bad.RankingScore < 0) {
+		t.Errorf("expected ranking scores to be negative and ordered, got %g and %g", bad.RankingScore, catastrophic.RankingScore)
+	}
+}
+
+func TestRankingScore(t *testing.T) {
+	converter := &DefaultScoreTrustworthinessConverter{}
+
+	testCases := []struct {
+		trustworthiness float64
+		expected        float64
+	}{
+		{1, 1},
+		{0.9, converter.ScoreFromTrustworthiness(0.9)},
+		{minTrustworthiness, 0},
+		{minTrustworthiness * minTrustworthiness, -1},
+		{math.Pow(minTrustworthiness, 5), -4},
+	}
+
+	for _, tc := range testCases {
+		actual := rankingScore(converter.ScoreFromTrustworthiness(tc.trustworthiness), math.Log(tc.trustworthiness), minTrustworthiness)
+		if math.Abs(actual-tc.expected) > 1e-9 {
+			t.Errorf("ranking score of %g: expected %g, got %g", tc.trustworthiness, tc.expected, actual)
+		}
+	}
+}
+
+func TestEvaluateTrustworthiness(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.8, "C": 0.95},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"B", "C"}, "B": {}, "C": {}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	components, err := evaluator.EvaluateTrustworthiness(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedTransitive := math.Pow(0.8, 1.5) * math.Pow(0.95, 1.5)
+
+	for _, each := range []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"intrinsic", components.Intrinsic, 0.9},
+		{"transitive", components.Transitive, expectedTransitive},
+		{"aggregated", components.Aggregated, 0.9 * expectedTransitive},
+		{"log aggregated", components.LogAggregated, math.Log(0.9 * expectedTransitive)},
+	} {
+		if math.Abs(each.actual-each.expected) > 1e-12 {
+			t.Errorf("%s: expected %f, got %f", each.name, each.expected, each.actual)
+		}
+	}
+
+	score, err := evaluator.EvaluateScore(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := (&DefaultScoreTrustworthinessConverter{}).ScoreFromTrustworthiness(components.Aggregated); score != expected {
+		t.Errorf("expected score %f, got %f", expected, score)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	testCases := []struct {
+		name       string
+		parameters Parameters
+		// expectedTransitive is the transitive trustworthiness of A, whose dependencies are B (0.8) and C (0.95)
+		expectedTransitive float64
+		err                bool
+	}{
+		{name: "defaults", expectedTransitive: math.Pow(0.8*0.95, 1.5)},
+		{name: "exponent", parameters: Parameters{TransitiveTrustworthinessExponent: 2}, expectedTransitive: math.Pow(0.8*0.95, 2)},
+		{name: "converter", parameters: Parameters{MinTrustworthiness: 0.5, TrustworthinessOffset: 10}, expectedTransitive: math.Pow(0.8*0.95, 1.5)},
+		{name: "negative exponent", parameters: Parameters{TransitiveTrustworthinessExponent: -1}, err: true},
+		{name: "min trustworthiness above 1", parameters: Parameters{MinTrustworthiness: 1.2}, err: true},
+		{name: "offset below 1", parameters: Parameters{TrustworthinessOffset: 0.5}, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator, err := NewEvaluator(
+				&testIntrinsicTrustworthinessEvaluator{
+					trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.8, "C": 0.95},
+				},
+				&testDependencyResolver{
+					directDependencyNamesByName: map[string][]string{"A": {"B", "C"}, "B": {}, "C": {}},
+				},
+				WithParameters(tc.parameters),
+				WithEvaluationTree(),
+			)
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error for %+v", tc.parameters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if math.Abs(evaluation.Components.Transitive-tc.expectedTransitive) > 1e-12 {
+				t.Errorf("expected transitive trustworthiness %f, got %f", tc.expectedTransitive, evaluation.Components.Transitive)
+			}
+
+			converter := evaluator.Parameters().Converter()
+			if expected := converter.ScoreFromTrustworthiness(evaluation.Trustworthiness); evaluation.Score != expected {
+				t.Errorf("expected score %f, got %f", expected, evaluation.Score)
+			}
+
+			var sum float64
+			for _, contribution := range evaluation.Tree.LogContributions() {
+				sum += contribution
+			}
+			if math.Abs(sum-evaluation.LogTrustworthiness) > 1e-12 {
+				t.Errorf("expected log contributions to sum to %f, got %f", evaluation.LogTrustworthiness, sum)
+			}
+		})
+	}
+}
+
+func TestInvalidIntrinsicFallback(t *testing.T) {
+	for _, fallback := range []float64{-0.5, 0, 1.5, math.NaN()} {
+		_, err := NewEvaluator(
+			&testIntrinsicTrustworthinessEvaluator{},
+			&testDependencyResolver{},
+			WithIntrinsicFallback(fallback),
+		)
+		if err == nil {
+			t.Errorf("expected an error for intrinsic fallback %g", fallback)
+		}
+	}
+}
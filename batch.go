@@ -0,0 +1,336 @@
+package aggregdepscore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultConcurrency is the number of root packages evaluated concurrently by batch evaluations
+const defaultConcurrency = 8
+
+// ScoreResult is the result of the evaluation of one package of a batch.
+type ScoreResult struct {
+	// Index is the position of the package in the input
+	Index   int
+	Package Package
+	// Evaluation is nil if Err is not nil
+	Evaluation *Evaluation
+	Err        error
+}
+
+// WithConcurrency sets the number of root packages evaluated concurrently
+// by EvaluateScores and EvaluateScoresStream; the default is 8.
+//
+// Note that observers, the intrinsic evaluator and the dependency resolver
+// are then called from several goroutines.
+func WithConcurrency(n int) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.concurrency = n
+	}
+}
+
+// EvaluateScores evaluates many root packages.
+// Results are returned in the order of packages;
+// an error evaluating one package does not prevent the evaluation of the others.
+//
+// The intrinsic trustworthiness and the direct dependencies of each package
+// are looked up only once for the whole batch,
+// so evaluating packages with common dependencies is much cheaper than with separate calls to Evaluate.
+func (e *Evaluator) EvaluateScores(ctx context.Context, packages []Package) []ScoreResult {
+	in := make(chan Package)
+	go func() {
+		defer close(in)
+		for _, p := range packages {
+			select {
+			case in <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]ScoreResult, len(packages))
+	for i, p := range packages {
+		results[i] = ScoreResult{Index: i, Package: p}
+	}
+
+	for result := range e.EvaluateScoresStream(ctx, in) {
+		results[result.Index] = result
+	}
+
+	// packages that were not sent because of cancellation
+	for i := range results {
+		if results[i].Evaluation == nil && results[i].Err == nil {
+			results[i].Err = context.Cause(ctx)
+		}
+	}
+
+	return results
+}
+
+// EvaluateScoresStream is like EvaluateScores but reads packages from a channel,
+// which is suited to very long lists of packages.
+// Results are sent as soon as they are available, so not necessarily in input order
+// (see ScoreResult.Index).
+// The returned channel is closed once in is closed and all its packages are evaluated,
+// or once ctx is cancelled.
+//
+// Memoized lookups are kept for the whole stream,
+// so memory usage grows with the number of distinct packages encountered.
+func (e *Evaluator) EvaluateScoresStream(ctx context.Context, in <-chan Package) <-chan ScoreResult {
+	concurrency := e.concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	memo := newEvaluationMemo(e.trustworthiness.intrinsic, e.trustworthiness.deps, e.metrics)
+	trustworthiness := e.trustworthiness
+	trustworthiness.intrinsic = memo
+	trustworthiness.deps = memo
+
+	type job struct {
+		index int
+		pkg   Package
+	}
+
+	jobs := make(chan job)
+	out := make(chan ScoreResult)
+
+	go func() {
+		defer close(jobs)
+		index := 0
+		for {
+			select {
+			case p, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- job{index: index, pkg: p}:
+				case <-ctx.Done():
+					return
+				}
+				index++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				evaluation, err := e.evaluate(ctx, &trustworthiness, j.pkg)
+
+				select {
+				case out <- ScoreResult{Index: j.index, Package: j.pkg, Evaluation: evaluation, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// evaluationMemo memoizes the intrinsic trustworthiness and the direct dependencies of packages
+// during a batch evaluation.
+// Concurrent lookups of the same package wait for the first one.
+type evaluationMemo struct {
+	intrinsic IntrinsicTrustworthinessEvaluator
+	deps      DependencyResolver
+	metrics   *Metrics
+
+	// ttl is how long lookups are kept, forever if zero (see WithCache)
+	ttl time.Duration
+
+	mutex           sync.Mutex
+	trustworthiness map[Package]*memoEntry[float64]
+	dependencies    map[Package]*memoEntry[[]Package]
+	// nbInserts is used to sweep expired entries regularly
+	nbInserts int
+}
+
+// compile-time interface checks
+var _ IntrinsicTrustworthinessEvaluator = &evaluationMemo{}
+var _ DependencyResolver = &evaluationMemo{}
+
+type memoEntry[T any] struct {
+	done    chan struct{}
+	value   T
+	err     error
+	expires time.Time
+}
+
+// expired tells if the lookup of the entry is done and expired
+func (e *memoEntry[T]) expired(ttl time.Duration, now time.Time) bool {
+	if ttl <= 0 {
+		return false
+	}
+
+	select {
+	case <-e.done:
+		return now.After(e.expires)
+	default:
+		return false
+	}
+}
+
+func newEvaluationMemo(intrinsic IntrinsicTrustworthinessEvaluator, deps DependencyResolver, metrics *Metrics) *evaluationMemo {
+	return &evaluationMemo{
+		intrinsic:       intrinsic,
+		deps:            deps,
+		metrics:         metrics,
+		trustworthiness: make(map[Package]*memoEntry[float64]),
+		dependencies:    make(map[Package]*memoEntry[[]Package]),
+	}
+}
+
+func (m *evaluationMemo) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
+	intrinsic, _, err := m.lookupIntrinsic(ctx, p)
+	return intrinsic, err
+}
+
+func (m *evaluationMemo) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	deps, _, err := m.lookupDependencies(ctx, p)
+	return deps, err
+}
+
+func (m *evaluationMemo) lookupIntrinsic(ctx context.Context, p Package) (float64, bool, error) {
+	return memoize(ctx, m, m.trustworthiness, "intrinsic_trustworthiness", p, func(ctx context.Context, p Package) (float64, bool, error) {
+		return lookupIntrinsic(ctx, m.intrinsic, p)
+	})
+}
+
+func (m *evaluationMemo) lookupDependencies(ctx context.Context, p Package) ([]Package, bool, error) {
+	return memoize(ctx, m, m.dependencies, "direct_dependencies", p, func(ctx context.Context, p Package) ([]Package, bool, error) {
+		return lookupDependencies(ctx, m.deps, p)
+	})
+}
+
+// lookupIntrinsic evaluates the intrinsic trustworthiness of p
+// and tells if the underlying evaluator was called, which is not the case when the lookup is memoized
+func lookupIntrinsic(ctx context.Context, intrinsic IntrinsicTrustworthinessEvaluator, p Package) (float64, bool, error) {
+	if m, ok := intrinsic.(*evaluationMemo); ok {
+		return m.lookupIntrinsic(ctx, p)
+	}
+
+	t, err := intrinsic.EvaluateIntrinsicTrustworthiness(ctx, p)
+	return t, true, err
+}
+
+// lookupDependencies is like lookupIntrinsic for the direct dependencies of p
+func lookupDependencies(ctx context.Context, deps DependencyResolver, p Package) ([]Package, bool, error) {
+	if m, ok := deps.(*evaluationMemo); ok {
+		return m.lookupDependencies(ctx, p)
+	}
+
+	result, err := deps.GetDirectDependencies(ctx, p)
+	return result, true, err
+}
+
+// intrinsicMemoized tells if the intrinsic trustworthiness of p is memoized or being looked up,
+// so that evaluating it would not call the underlying evaluator
+func intrinsicMemoized(intrinsic IntrinsicTrustworthinessEvaluator, p Package) bool {
+	m, ok := intrinsic.(*evaluationMemo)
+	return ok && (memoized(m, m.trustworthiness, p) || intrinsicMemoized(m.intrinsic, p))
+}
+
+// dependenciesMemoized is like intrinsicMemoized for the direct dependencies of p
+func dependenciesMemoized(deps DependencyResolver, p Package) bool {
+	m, ok := deps.(*evaluationMemo)
+	return ok && (memoized(m, m.dependencies, p) || dependenciesMemoized(m.deps, p))
+}
+
+func memoized[T any](m *evaluationMemo, entries map[Package]*memoEntry[T], p Package) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := entries[p]
+	return ok && !entry.expired(m.ttl, time.Now())
+}
+
+// memoize returns the memoized lookup of p, calling lookup if needed;
+// like lookup, it tells if the underlying evaluator or resolver was called
+func memoize[T any](ctx context.Context, m *evaluationMemo, entries map[Package]*memoEntry[T], cache string, p Package, lookup func(context.Context, Package) (T, bool, error)) (T, bool, error) {
+	for {
+		m.mutex.Lock()
+		entry, ok := entries[p]
+		if ok && entry.expired(m.ttl, time.Now()) {
+			ok = false
+		}
+		if !ok {
+			entry = &memoEntry[T]{done: make(chan struct{})}
+			entries[p] = entry
+			m.inserted()
+		}
+		m.mutex.Unlock()
+
+		if m.metrics != nil {
+			m.metrics.cacheLookup(cache, ok)
+		}
+
+		if ok {
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				var zero T
+				return zero, false, context.Cause(ctx)
+			}
+
+			// the lookup was cancelled by the evaluation that started it, not by this one
+			if isCancellation(entry.err) && ctx.Err() == nil {
+				continue
+			}
+
+			return entry.value, false, entry.err
+		}
+
+		var called bool
+		entry.value, called, entry.err = lookup(ctx, p)
+		entry.expires = time.Now().Add(m.ttl)
+
+		// errors due to the cancellation of this evaluation must not affect other evaluations,
+		// and long-lived caches must not keep errors, which may be transient
+		if entry.err != nil && (m.ttl > 0 || isCancellation(entry.err)) {
+			m.mutex.Lock()
+			if entries[p] == entry {
+				delete(entries, p)
+			}
+			m.mutex.Unlock()
+		}
+
+		close(entry.done)
+
+		return entry.value, called, entry.err
+	}
+}
+
+// isCancellation tells if err is due to a cancelled or expired context,
+// including on the server side of an RPC
+func isCancellation(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
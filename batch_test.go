@@ -0,0 +1,181 @@
+package aggregdepscore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+// testConcurrentEvaluator is safe for concurrent use and counts lookups
+type testConcurrentEvaluator struct {
+	trustworthinessByName       map[string]float64
+	directDependencyNamesByName map[string][]string
+
+	mutex sync.Mutex
+	calls map[string]int
+}
+
+func (e *testConcurrentEvaluator) count(call string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.calls == nil {
+		e.calls = make(map[string]int)
+	}
+	e.calls[call]++
+}
+
+func (e *testConcurrentEvaluator) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
+	e.count("intrinsic " + p.Name)
+
+	if err := ctx.Err(); err != nil {
+		return 0.0, err
+	}
+
+	t, ok := e.trustworthinessByName[p.Name]
+	if !ok {
+		return 0.0, fmt.Errorf("unknown package %q", p.Name)
+	}
+
+	return t, nil
+}
+
+func (e *testConcurrentEvaluator) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	e.count("deps " + p.Name)
+
+	var deps []Package
+	for _, name := range e.directDependencyNamesByName[p.Name] {
+		deps = append(deps, Package{Name: name})
+	}
+
+	return deps, nil
+}
+
+func TestEvaluateScores(t *testing.T) {
+	backend := &testConcurrentEvaluator{
+		trustworthinessByName: map[string]float64{
+			"A":      0.9,
+			"B":      0.95,
+			"common": 0.85,
+		},
+		directDependencyNamesByName: map[string][]string{
+			"A":      {"common"},
+			"B":      {"common"},
+			"common": {},
+		},
+	}
+
+	evaluator, err := NewEvaluator(backend, backend, WithConcurrency(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packages := []Package{{Name: "A"}, {Name: "unknown"}, {Name: "B"}, {Name: "A"}}
+	results := evaluator.EvaluateScores(context.Background(), packages)
+
+	if len(results) != len(packages) {
+		t.Fatalf("expected %d results, got %d", len(packages), len(results))
+	}
+
+	for i, result := range results {
+		if result.Index != i || result.Package != packages[i] {
+			t.Errorf("result %d is out of order: %+v", i, result)
+		}
+	}
+
+	if results[1].Err == nil {
+		t.Errorf("expected an error for the unknown package")
+	}
+
+	for _, call := range []string{"intrinsic common", "deps common", "intrinsic A"} {
+		if backend.calls[call] != 1 {
+			t.Errorf("expected %q to be looked up once, got %d", call, backend.calls[call])
+		}
+	}
+
+	for _, i := range []int{0, 2, 3} {
+		if results[i].Err != nil {
+			t.Fatalf("unexpected error for %s: %v", packages[i].Name, results[i].Err)
+		}
+
+		expected, err := evaluator.Evaluate(context.Background(), packages[i])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if math.Abs(results[i].Evaluation.Score-expected.Score) > 1e-12 {
+			t.Errorf("score of %s: expected %f, got %f", packages[i].Name, expected.Score, results[i].Evaluation.Score)
+		}
+	}
+}
+
+func TestEvaluateScoresStream(t *testing.T) {
+	backend := &testConcurrentEvaluator{
+		trustworthinessByName:       make(map[string]float64),
+		directDependencyNamesByName: make(map[string][]string),
+	}
+
+	const n = 100
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("p%d", i)
+		backend.trustworthinessByName[name] = 0.9
+		backend.directDependencyNamesByName[name] = []string{"shared"}
+	}
+	backend.trustworthinessByName["shared"] = 0.99
+
+	evaluator, err := NewEvaluator(backend, backend)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := make(chan Package)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- Package{Name: fmt.Sprintf("p%d", i)}
+		}
+	}()
+
+	seen := make(map[int]bool)
+	for result := range evaluator.EvaluateScoresStream(context.Background(), in) {
+		if result.Err != nil {
+			t.Fatalf("unexpected error: %v", result.Err)
+		}
+		if result.Package.Name != fmt.Sprintf("p%d", result.Index) {
+			t.Errorf("index %d does not match package %s", result.Index, result.Package.Name)
+		}
+		seen[result.Index] = true
+	}
+
+	if len(seen) != n {
+		t.Errorf("expected %d results, got %d", n, len(seen))
+	}
+
+	if backend.calls["intrinsic shared"] != 1 {
+		t.Errorf("expected shared dependency to be evaluated once, got %d", backend.calls["intrinsic shared"])
+	}
+}
+
+func TestEvaluateScoresCancelled(t *testing.T) {
+	backend := &testConcurrentEvaluator{
+		trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.9},
+	}
+
+	evaluator, err := NewEvaluator(backend, backend)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := evaluator.EvaluateScores(ctx, []Package{{Name: "A"}, {Name: "B"}})
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected %s to fail with context.Canceled, got %v", result.Package.Name, result.Err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package aggregdepscore
+
+import "time"
+
+// sweepInterval is the number of insertions in a cache between sweeps of its expired entries
+const sweepInterval = 1024
+
+// WithCache makes the evaluator keep the intrinsic trustworthiness and the direct dependencies
+// of packages for ttl, across evaluations, which suits long-lived evaluators such as servers.
+// Failed lookups are not kept.
+//
+// Like batch evaluations, concurrent lookups of the same package wait for the first one.
+// Memory usage grows with the number of distinct packages evaluated within ttl.
+func WithCache(ttl time.Duration) EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.cacheTTL = ttl
+	}
+}
+
+// inserted sweeps expired entries every sweepInterval insertions;
+// the mutex of m must be held
+func (m *evaluationMemo) inserted() {
+	if m.ttl <= 0 {
+		return
+	}
+
+	m.nbInserts++
+	if m.nbInserts%sweepInterval != 0 {
+		return
+	}
+
+	now := time.Now()
+	for p, entry := range m.trustworthiness {
+		if entry.expired(m.ttl, now) {
+			delete(m.trustworthiness, p)
+		}
+	}
+	for p, entry := range m.dependencies {
+		if entry.expired(m.ttl, now) {
+			delete(m.dependencies, p)
+		}
+	}
+}
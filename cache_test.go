@@ -0,0 +1,182 @@
+package aggregdepscore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		name string
+		ttl  time.Duration
+		// expected number of lookups of the intrinsic trustworthiness of C after two evaluations
+		expectedCalls int
+	}{
+		{name: "without cache", ttl: 0, expectedCalls: 4},
+		{name: "with cache", ttl: time.Hour, expectedCalls: 1},
+		{name: "expired", ttl: time.Nanosecond, expectedCalls: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &testConcurrentEvaluator{
+				trustworthinessByName:       map[string]float64{"A": 0.9, "B": 0.95, "C": 0.8},
+				directDependencyNamesByName: map[string][]string{"A": {"B", "C"}, "B": {"C"}},
+			}
+
+			evaluator, err := NewEvaluator(backend, backend, WithCache(tc.ttl))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if tc.ttl == time.Nanosecond {
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			if backend.calls["intrinsic C"] != tc.expectedCalls {
+				t.Errorf("expected %d lookups of C, got %d", tc.expectedCalls, backend.calls["intrinsic C"])
+			}
+		})
+	}
+}
+
+func TestCacheDoesNotKeepErrors(t *testing.T) {
+	backend := &testConcurrentEvaluator{
+		trustworthinessByName:       map[string]float64{"A": 0.9},
+		directDependencyNamesByName: map[string][]string{"A": {"B"}},
+	}
+
+	evaluator, err := NewEvaluator(backend, backend, WithCache(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err == nil {
+		t.Fatalf("expected an error for the unknown package B")
+	}
+
+	backend.mutex.Lock()
+	backend.trustworthinessByName["B"] = 0.95
+	backend.mutex.Unlock()
+
+	_, err = evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("expected the failed lookup to be retried, got %v", err)
+	}
+}
+
+// blockingEvaluator blocks the first lookup of the intrinsic trustworthiness
+// until it is released or its context is done
+type blockingEvaluator struct {
+	started chan struct{}
+	release chan struct{}
+	// err is returned by the first lookup once released
+	err   error
+	calls atomic.Int32
+}
+
+func (e *blockingEvaluator) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
+	if e.calls.Add(1) > 1 {
+		return 0.9, nil
+	}
+
+	close(e.started)
+	select {
+	case <-e.release:
+		return 0, e.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (e *blockingEvaluator) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	return nil, nil
+}
+
+func TestCacheWaitersRetryCancelledLookups(t *testing.T) {
+	testCases := []struct {
+		name string
+		// cancel makes the first lookup fail
+		cancel func(cancelFirst context.CancelFunc, release chan struct{})
+		err    error
+	}{
+		{
+			name: "cancelled context",
+			cancel: func(cancelFirst context.CancelFunc, release chan struct{}) {
+				cancelFirst()
+			},
+		},
+		{
+			name: "cancelled RPC",
+			cancel: func(cancelFirst context.CancelFunc, release chan struct{}) {
+				close(release)
+			},
+			err: status.Error(codes.Canceled, "context canceled"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &blockingEvaluator{started: make(chan struct{}), release: make(chan struct{}), err: tc.err}
+
+			evaluator, err := NewEvaluator(backend, backend, WithCache(time.Hour))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			first := make(chan error)
+			go func() {
+				_, err := evaluator.Evaluate(ctx, Package{Name: "A"})
+				first <- err
+			}()
+			<-backend.started
+
+			second := make(chan error)
+			go func() {
+				_, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+				second <- err
+			}()
+
+			// let the second evaluation wait for the lookup started by the first one
+			time.Sleep(20 * time.Millisecond)
+			tc.cancel(cancel, backend.release)
+
+			if err := <-first; err == nil {
+				t.Errorf("expected the first evaluation to fail")
+			}
+
+			if err := <-second; err != nil {
+				t.Errorf("expected the second evaluation to retry the lookup, got %v", err)
+			}
+
+			if calls := backend.calls.Load(); calls != 2 {
+				t.Errorf("expected 2 lookups, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestInvalidCacheTTL(t *testing.T) {
+	backend := &testConcurrentEvaluator{}
+
+	_, err := NewEvaluator(backend, backend, WithCache(-time.Second))
+	if err == nil {
+		t.Errorf("expected an error for a negative TTL")
+	}
+}
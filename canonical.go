@@ -0,0 +1,74 @@
+package aggregdepscore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Canonicalizer maps a package to a canonical identity,
+// so that different ways of referring to the same package
+// (e.g. "Django" and "django" on PyPI)
+// are recognized as the same package.
+type Canonicalizer interface {
+	Canonicalize(p Package) Package
+}
+
+// DefaultCanonicalizer normalizes ecosystems and package names
+// following the rules of each ecosystem.
+// Versions are ignored unless IncludeVersion is true.
+type DefaultCanonicalizer struct {
+	IncludeVersion bool
+}
+
+// compile-time interface checks
+var _ Canonicalizer = &DefaultCanonicalizer{}
+
+// see https://peps.python.org/pep-0503/#normalized-names
+var pypiSeparatorsRegexp = regexp.MustCompile(`[-_.]+`)
+
+func (c *DefaultCanonicalizer) Canonicalize(p Package) Package {
+	result := Package{
+		Ecosystem: strings.ToLower(strings.TrimSpace(p.Ecosystem)),
+		Name:      strings.TrimSpace(p.Name),
+	}
+
+	if c.IncludeVersion {
+		result.Version = strings.TrimSpace(p.Version)
+	}
+
+	switch result.Ecosystem {
+	case "pypi":
+		result.Name = pypiSeparatorsRegexp.ReplaceAllString(strings.ToLower(result.Name), "-")
+	case "crates.io":
+		// crates.io considers "-" and "_" to be the same
+		result.Name = strings.ReplaceAll(strings.ToLower(result.Name), "_", "-")
+	case "nuget":
+		result.Name = strings.ToLower(result.Name)
+	case "go":
+		result.Name = canonicalGoModulePath(result.Name)
+	}
+
+	return result
+}
+
+// canonicalGoModulePath maps gopkg.in paths to the repository they redirect to,
+// keeping the major version if it is greater than 1
+// (e.g. "gopkg.in/yaml.v3" becomes "github.com/go-yaml/yaml/v3")
+func canonicalGoModulePath(name string) string {
+	if !strings.HasPrefix(name, "gopkg.in/") {
+		return name
+	}
+
+	repository, err := getGopkginRepository(name)
+	if err != nil {
+		return name
+	}
+
+	major := name[strings.LastIndex(name, ".v")+len(".v"):]
+	if major == "0" || major == "1" {
+		return repository
+	}
+
+	return fmt.Sprintf("%s/v%s", repository, major)
+}
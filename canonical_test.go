@@ -0,0 +1,32 @@
+package aggregdepscore
+
+import "testing"
+
+func TestDefaultCanonicalizer(t *testing.T) {
+	for _, each := range []struct {
+		input    Package
+		expected Package
+	}{
+		{Package{"pypi", "Django", "5.0"}, Package{"pypi", "django", ""}},
+		{Package{"PyPI", "zope.interface", "6.0"}, Package{"pypi", "zope-interface", ""}},
+		{Package{"pypi", "typing__extensions", "4.0"}, Package{"pypi", "typing-extensions", ""}},
+		{Package{"crates.io", "serde_json", "1.0"}, Package{"crates.io", "serde-json", ""}},
+		{Package{"nuget", "Newtonsoft.Json", "13.0"}, Package{"nuget", "newtonsoft.json", ""}},
+		{Package{"npm", "JSONStream", "1.3.5"}, Package{"npm", "JSONStream", ""}},
+		{Package{"go", "gopkg.in/yaml.v3", "v3.0.1"}, Package{"go", "github.com/go-yaml/yaml/v3", ""}},
+		{Package{"go", "gopkg.in/DataDog/dd-trace-go.v1", "v1.0.0"}, Package{"go", "github.com/DataDog/dd-trace-go", ""}},
+		{Package{"go", "github.com/Foo/bar", "v1.0.0"}, Package{"go", "github.com/Foo/bar", ""}},
+	} {
+		t.Run(each.input.Name, func(t *testing.T) {
+			actual := (&DefaultCanonicalizer{}).Canonicalize(each.input)
+			if actual != each.expected {
+				t.Errorf("Expected %v, but got %v", each.expected, actual)
+			}
+		})
+	}
+
+	withVersion := (&DefaultCanonicalizer{IncludeVersion: true}).Canonicalize(Package{"pypi", "Django", "5.0"})
+	if withVersion.Version != "5.0" {
+		t.Errorf("Expected version to be kept, got %v", withVersion)
+	}
+}
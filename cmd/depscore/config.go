@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// configEnv is the environment variable of the configuration file when -config is not set
+const configEnv = "DEPSCORE_CONFIG"
+
+var configFile = flag.String("config", "", "JSON configuration file, also read from $"+configEnv+" (optional)")
+
+// config is the configuration file read by parseFlags, nil without one
+var config *fileConfig
+
+// fileConfig is the configuration file of depscore.
+// A field tagged with flag is the value of the flag of that name
+// unless the flag is set on the command line or by its environment variable (see envName);
+// other flags, such as the evaluated package or the output format, can only be set on the command line.
+type fileConfig struct {
+	Model struct {
+		MinTrustworthiness    *float64 `json:"min_trustworthiness" flag:"min-trustworthiness"`
+		TrustworthinessOffset *float64 `json:"trustworthiness_offset" flag:"trustworthiness-offset"`
+		TransitiveExponent    *float64 `json:"transitive_exponent" flag:"transitive-exponent"`
+		IntrinsicFallback     *float64 `json:"intrinsic_fallback" flag:"intrinsic-fallback"`
+		TrustDomains          *string  `json:"trust_domains" flag:"trust-domains"`
+	} `json:"model"`
+	Cache struct {
+		TTL *duration `json:"ttl" flag:"cache-ttl"`
+	} `json:"cache"`
+	DepsDotDev struct {
+		RepositorySelection *string   `json:"repository_selection" flag:"repository-selection"`
+		Retries             *int      `json:"retries" flag:"retries"`
+		RetryBackoff        *duration `json:"retry_backoff" flag:"retry-backoff"`
+		RateLimit           *float64  `json:"rate_limit" flag:"rate-limit"`
+		GoVanityLookup      *bool     `json:"go_vanity_lookup" flag:"go-vanity-lookup"`
+	} `json:"deps_dev"`
+	Limits struct {
+		MaxDepth                  *int     `json:"max_depth" flag:"max-depth"`
+		MaxNodes                  *int     `json:"max_nodes" flag:"max-nodes"`
+		MaxCalls                  *int     `json:"max_calls" flag:"max-calls"`
+		Truncation                *string  `json:"truncation" flag:"truncation"`
+		TruncationTrustworthiness *float64 `json:"truncation_trustworthiness" flag:"truncation-trustworthiness"`
+	} `json:"limits"`
+	Scope struct {
+		Ecosystems []string `json:"ecosystems" flag:"scope-ecosystems"`
+		Exclude    []string `json:"exclude" flag:"scope-exclude"`
+	} `json:"scope"`
+	Thresholds struct {
+		MinScore            *float64 `json:"min_score" flag:"min-score"`
+		LowTrust            *float64 `json:"low_trust" flag:"low-trust"`
+		RegressionTolerance *float64 `json:"regression_tolerance" flag:"regression-tolerance"`
+	} `json:"thresholds"`
+	Log struct {
+		Level  *string `json:"level" flag:"log-level"`
+		Format *string `json:"format" flag:"log-format"`
+	} `json:"log"`
+	OverridesFile *string `json:"overrides_file" flag:"overrides"`
+	// Overrides are trust overrides used when there is no overrides file
+	Overrides []aggregdepscore.OverrideRule `json:"overrides"`
+}
+
+// duration is a time.Duration written as a string like "1h30m" in the configuration file
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("expected a duration like \"1h30m\": %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = duration(v)
+	return nil
+}
+
+// loadConfig reads a configuration file, rejecting unknown fields
+func loadConfig(filename string) (*fileConfig, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	var c fileConfig
+	err = decoder.Decode(&c)
+	if err != nil {
+		return nil, fmt.Errorf("parsing configuration file %s: %w", filename, err)
+	}
+
+	err = c.validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating configuration file %s: %w", filename, err)
+	}
+
+	return &c, nil
+}
+
+func (c *fileConfig) validate() error {
+	if c.OverridesFile != nil && len(c.Overrides) > 0 {
+		return errors.New("overrides and overrides_file are mutually exclusive")
+	}
+
+	if c.Cache.TTL != nil && *c.Cache.TTL < 0 {
+		return fmt.Errorf("cache ttl must be positive, got %s", time.Duration(*c.Cache.TTL))
+	}
+
+	err := (&aggregdepscore.Overrides{Rules: c.Overrides}).Validate()
+	if err != nil {
+		return fmt.Errorf("overrides: %w", err)
+	}
+
+	return nil
+}
+
+// flagValues returns the values of the flags set by c, by flag name
+func (c *fileConfig) flagValues() map[string]string {
+	values := make(map[string]string)
+	collectFlagValues(reflect.ValueOf(c).Elem(), values, nil)
+
+	return values
+}
+
+// configurableFlags returns the names of the flags that can be set
+// by the configuration file and by environment variables
+func configurableFlags() map[string]bool {
+	names := make(map[string]bool)
+	collectFlagValues(reflect.ValueOf(&fileConfig{}).Elem(), nil, names)
+
+	return names
+}
+
+// collectFlagValues adds the flags set by v to values (if not nil),
+// and the names of all the flags of v to names (if not nil)
+func collectFlagValues(v reflect.Value, values map[string]string, names map[string]bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+
+		name, ok := t.Field(i).Tag.Lookup("flag")
+		if !ok {
+			if field.Kind() == reflect.Struct {
+				collectFlagValues(field, values, names)
+			}
+			continue
+		}
+
+		if names != nil {
+			names[name] = true
+		}
+
+		if values == nil {
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.Pointer && !field.IsNil():
+			values[name] = formatFlagValue(field.Elem().Interface())
+		case field.Kind() == reflect.Slice && !field.IsNil():
+			values[name] = strings.Join(field.Interface().([]string), ",")
+		}
+	}
+}
+
+func formatFlagValue(v any) string {
+	if d, ok := v.(duration); ok {
+		return time.Duration(d).String()
+	}
+
+	return fmt.Sprint(v)
+}
+
+// envName is the environment variable of a flag, e.g. DEPSCORE_MIN_SCORE for -min-score
+func envName(flagName string) string {
+	return "DEPSCORE_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// parseFlags parses the command line, then reads the configuration file (see -config);
+// flags take precedence over environment variables, which take precedence over the configuration file.
+// Errors are flagErrors.
+func parseFlags() error {
+	flag.Parse()
+
+	filename := *configFile
+	if filename == "" {
+		filename = os.Getenv(configEnv)
+	}
+
+	if filename != "" {
+		c, err := loadConfig(filename)
+		if err != nil {
+			return &flagError{err: err}
+		}
+		config = c
+	}
+
+	err := applyConfig(flag.CommandLine, config, os.LookupEnv)
+	if err != nil {
+		return &flagError{err: err}
+	}
+
+	return nil
+}
+
+// applyConfig sets the configurable flags of fs (see fileConfig) that are not set on the command line
+// from their environment variable, or else from c (which can be nil);
+// values of flags that fs does not define are ignored
+func applyConfig(fs *flag.FlagSet, c *fileConfig, lookupEnv func(string) (string, bool)) error {
+	configurable := configurableFlags()
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var values map[string]string
+	if c != nil {
+		values = c.flagValues()
+	}
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] || !configurable[f.Name] {
+			return
+		}
+
+		if value, ok := lookupEnv(envName(f.Name)); ok {
+			err := fs.Set(f.Name, value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for $%s: %w", value, envName(f.Name), err))
+			}
+			return
+		}
+
+		if value, ok := values[f.Name]; ok {
+			err := fs.Set(f.Name, value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for -%s in configuration file: %w", value, f.Name, err))
+			}
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// runConfig runs the config subcommands;
+// "config validate" checks the configuration file and environment variables
+// as the other subcommands would use them
+func runConfig() error {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s config validate [-config <file>]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	if len(os.Args) < 2 || os.Args[1] != "validate" {
+		flag.Usage()
+		return &flagError{err: errors.New("expected a config subcommand: validate")}
+	}
+	os.Args = append(os.Args[:1], os.Args[2:]...)
+
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	if config == nil {
+		return &flagError{err: fmt.Errorf("no configuration file, set -config or $%s", configEnv)}
+	}
+
+	err = validateThresholds()
+	if err != nil {
+		return &flagError{err: fmt.Errorf("validating configuration: %w", err)}
+	}
+
+	// there is nothing to display progress of
+	*progress = false
+
+	a, err := newApp()
+	if err != nil {
+		return err
+	}
+	a.close()
+
+	fmt.Println("configuration is valid")
+	return nil
+}
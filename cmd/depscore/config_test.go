@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+const testConfig = `{
+  "model": {"min_trustworthiness": 0.7, "transitive_exponent": 2},
+  "cache": {"ttl": "10m"},
+  "deps_dev": {"retries": 3, "rate_limit": 20},
+  "scope": {"ecosystems": ["npm", "pypi"], "exclude": ["@acme/*"]},
+  "thresholds": {"min_score": 0.2},
+  "overrides": [{"ecosystem": "npm", "name_glob": "@acme/*", "trustworthiness": 1}]
+}
+`
+
+func writeTestConfig(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "depscore.json")
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return filename
+}
+
+// testFlagSet defines some of the flags of depscore on a new flag set
+func testFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("depscore", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Float64("min-trustworthiness", 0.8, "")
+	fs.Float64("transitive-exponent", 1.5, "")
+	fs.Duration("cache-ttl", time.Hour, "")
+	fs.Int("retries", 0, "")
+	fs.Float64("rate-limit", 0, "")
+	fs.String("scope-ecosystems", "", "")
+	fs.String("scope-exclude", "", "")
+	fs.Float64("min-score", -1, "")
+	fs.String("format", "text", "")
+	fs.String("version", "", "")
+
+	return fs
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	c, err := loadConfig(writeTestConfig(t, testConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "configuration file",
+			expected: map[string]string{
+				"min-trustworthiness": "0.7",
+				"transitive-exponent": "2",
+				"cache-ttl":           "10m0s",
+				"retries":             "3",
+				"rate-limit":          "20",
+				"scope-ecosystems":    "npm,pypi",
+				"scope-exclude":       "@acme/*",
+				"min-score":           "0.2",
+				"format":              "text",
+			},
+		},
+		{
+			name: "environment over configuration file",
+			env:  map[string]string{"DEPSCORE_MIN_SCORE": "0.3"},
+			expected: map[string]string{
+				"min-trustworthiness": "0.7",
+				"min-score":           "0.3",
+			},
+		},
+		{
+			name: "environment of flags not in the configuration file",
+			env:  map[string]string{"DEPSCORE_FORMAT": "json", "DEPSCORE_VERSION": "1.2.3"},
+			expected: map[string]string{
+				"format":  "text",
+				"version": "",
+			},
+		},
+		{
+			name: "flags over environment",
+			args: []string{"-min-score", "0.4", "-retries=0"},
+			env:  map[string]string{"DEPSCORE_MIN_SCORE": "0.3"},
+			expected: map[string]string{
+				"min-trustworthiness": "0.7",
+				"retries":             "0",
+				"min-score":           "0.4",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := testFlagSet()
+
+			err := fs.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lookupEnv := func(name string) (string, bool) {
+				value, ok := tc.env[name]
+				return value, ok
+			}
+
+			err = applyConfig(fs, c, lookupEnv)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for name, expected := range tc.expected {
+				if actual := fs.Lookup(name).Value.String(); actual != expected {
+					t.Errorf("expected -%s to be %q, got %q", name, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		env           map[string]string
+		expectedError string
+	}{
+		{name: "unknown field", content: `{"model": {"exponent": 2}}`, expectedError: `unknown field "exponent"`},
+		{name: "wrong type", content: `{"deps_dev": {"retries": "3"}}`, expectedError: "cannot unmarshal string"},
+		{name: "invalid duration", content: `{"cache": {"ttl": "soon"}}`, expectedError: "invalid duration"},
+		{name: "negative duration", content: `{"cache": {"ttl": "-1h"}}`, expectedError: "cache ttl must be positive"},
+		{name: "invalid override", content: `{"overrides": [{"name": "a", "name_glob": "b"}]}`, expectedError: "overrides: rule 0"},
+		{name: "overrides twice", content: `{"overrides_file": "overrides.json", "overrides": [{"name": "a", "trustworthiness": 1}]}`, expectedError: "mutually exclusive"},
+		{name: "invalid environment variable", content: `{}`, env: map[string]string{"DEPSCORE_RETRIES": "many"}, expectedError: "$DEPSCORE_RETRIES"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := loadConfig(writeTestConfig(t, tc.content))
+			if err == nil {
+				lookupEnv := func(name string) (string, bool) {
+					value, ok := tc.env[name]
+					return value, ok
+				}
+
+				err = applyConfig(testFlagSet(), c, lookupEnv)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	packages := []aggregdepscore.Package{
+		{Ecosystem: "npm", Name: "express", Version: "4.18.2"},
+		{Ecosystem: "npm", Name: "@acme/internal", Version: "1.0.0"},
+		{Ecosystem: "pypi", Name: "requests", Version: "2.28.1"},
+		{Ecosystem: "go", Name: "golang.org/x/net", Version: "v0.30.0"},
+	}
+
+	testCases := []struct {
+		name       string
+		ecosystems string
+		exclude    string
+		expected   []string
+	}{
+		{name: "everything", expected: []string{"express", "@acme/internal", "requests", "golang.org/x/net"}},
+		{name: "ecosystems", ecosystems: "npm, pypi", expected: []string{"express", "@acme/internal", "requests"}},
+		{name: "exclusions", exclude: "@acme/*,golang.org/x/*", expected: []string{"express", "requests"}},
+		{name: "both", ecosystems: "npm", exclude: "@acme/*", expected: []string{"express"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := newScope(tc.ecosystems, tc.exclude)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, p := range s.filter(packages) {
+				actual = append(actual, p.Name)
+			}
+
+			if strings.Join(actual, " ") != strings.Join(tc.expected, " ") {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+
+	_, err := newScope("", "[a-")
+	if err == nil {
+		t.Errorf("expected an error for an invalid glob")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"time"
 
 	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
 )
@@ -13,49 +16,312 @@ var (
 	ecosystem   = flag.String("ecosystem", "", "Ecosystem of the package")
 	packageName = flag.String("package", "", "Name of the package")
 	version     = flag.String("version", "", "Version of the package")
+	overrides   = flag.String("overrides", "", "JSON file of trust overrides (optional)")
+	trustDomain = flag.String("trust-domains", "", "Group dependencies published from the same repository: worst or geometric-mean (optional)")
+	logLevel    = flag.String("log-level", "warn", "Log level: debug, info, warn or error")
+	logFormat   = flag.String("log-format", "text", "Log format: text or json")
+	fallback    = flag.Float64("intrinsic-fallback", -1, "Intrinsic trustworthiness assumed for packages that cannot be evaluated, greater than 0 and at most 1 (disabled if negative)")
+	dogstatsd   = flag.String("dogstatsd", os.Getenv("DD_DOGSTATSD_URL"), "DogStatsD address to emit scores to, e.g. udp://localhost:8125 or unix:///var/run/datadog/dsd.socket (optional)")
+	progress    = flag.Bool("progress", true, "Display a progress line on stderr when it is a terminal")
+	repoSelect  = flag.String("repository-selection", "first", "How to choose among several source repositories: verified, first, lowest or highest")
+	maxDepth    = flag.Int("max-depth", 0, "Maximum depth of fully evaluated dependencies (unlimited if 0)")
+	maxNodes    = flag.Int("max-nodes", 0, "Maximum number of package evaluations (unlimited if 0)")
+	maxCalls    = flag.Int("max-calls", 0, "Maximum number of deps.dev lookups, not counting cached ones (unlimited if 0)")
+	truncation  = flag.String("truncation", "fail", "What to do beyond limits: fail, assume or intrinsic-only")
+	format      = flag.String("format", "text", "Output format: text, json, csv, markdown or html, and sarif for projects")
+	assumed     = flag.Float64("truncation-trustworthiness", 0.8, "Aggregated trustworthiness assumed for truncated dependencies with -truncation=assume, greater than 0 and at most 1")
+	retries     = flag.Int("retries", 0, "Number of retries of deps.dev lookups failing with a transient error")
+	backoff     = flag.Duration("retry-backoff", time.Second, "Delay before the first retry of a deps.dev lookup, doubled at each retry")
+	rateLimit   = flag.Float64("rate-limit", 0, "Maximum number of deps.dev lookups per second (unlimited if 0)")
+	goVanity    = flag.Bool("go-vanity-lookup", false, "Follow the go-import meta tags of Go vanity import paths that are not well known, sending HTTPS requests to their hosts")
+	minTrust    = flag.Float64("min-trustworthiness", aggregdepscore.ModelParameters().MinTrustworthiness, "Trustworthiness of an OpenSSF scorecard score of 0")
+	trustOffset = flag.Float64("trustworthiness-offset", aggregdepscore.ModelParameters().TrustworthinessOffset, "Offset of the conversion of scores to trustworthiness (k in the design paper)")
+	exponent    = flag.Float64("transitive-exponent", aggregdepscore.ModelParameters().TransitiveTrustworthinessExponent, "Exponent of the aggregated trustworthiness of dependencies (e in the design paper)")
 )
 
+// maxRetryBackoff caps the delay between retries of deps.dev lookups
+const maxRetryBackoff = 30 * time.Second
+
+// commands are the subcommands of depscore;
+// without a subcommand, depscore evaluates a single package (see run).
+// Subcommands register their own flags before flags are parsed (see parseFlags).
+var commands = map[string]func() error{
+	"diff":    runDiff,
+	"explain": runExplain,
+	"graph":   runGraph,
+	"config":  runConfig,
+	"project": runProject,
+	"serve":   runServe,
+}
+
 func main() {
-	err := run()
+	command := run
+	if len(os.Args) > 1 {
+		if c, ok := commands[os.Args[1]]; ok {
+			command = c
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
+	err := command()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "ERROR: "+err.Error())
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func run() error {
-	flag.Parse()
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
 
-	err := validateFlags()
+	err = validateFlags()
 	if err != nil {
 		flag.Usage()
-		return fmt.Errorf("validating flags: %w", err)
+		return &flagError{err: fmt.Errorf("validating flags: %w", err)}
 	}
 
-	depsdotdev, err := aggregdepscore.NewDepsDotDevClient()
+	a, err := newApp(formatOptions(*format)...)
 	if err != nil {
-		return fmt.Errorf("creating deps.dev client: %w", err)
+		return err
 	}
+	defer a.close()
 
-	evaluator, err := aggregdepscore.NewEvaluator(depsdotdev, depsdotdev)
+	start := time.Now()
+	evaluation, err := a.evaluate(context.Background(), packageFromFlags())
+	if err != nil {
+		return fmt.Errorf("evaluating score: %w", err)
+	}
+
+	if evaluation.Truncated {
+		a.logger.Warn("evaluation was truncated, the score is approximate", slog.Int("nb_truncations", len(evaluation.Truncations)))
+	}
+
+	results := []result{newResult(evaluation, time.Since(start))}
+
+	if *format == "html" {
+		err = writeHTML(os.Stdout, evaluation, results[0])
+	} else {
+		err = writeResults(os.Stdout, *format, results)
+	}
 	if err != nil {
-		return fmt.Errorf("creating evaluator: %w", err)
+		return fmt.Errorf("writing results: %w", err)
 	}
-	score, err := evaluator.EvaluateScore(context.Background(), aggregdepscore.Package{
+
+	return checkGates(os.Stderr, results)
+}
+
+// formatOptions returns the evaluator options required by an output format
+func formatOptions(format string) []aggregdepscore.EvaluatorOption {
+	if format == "html" {
+		return []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}
+	}
+
+	return nil
+}
+
+func packageFromFlags() aggregdepscore.Package {
+	return aggregdepscore.Package{
 		Ecosystem: *ecosystem,
 		Name:      *packageName,
 		Version:   *version,
-	})
+	}
+}
+
+// app holds what is created from the common flags
+type app struct {
+	logger    *slog.Logger
+	evaluator *aggregdepscore.Evaluator
+	scope     *scope
+	progress  *progressLine
+	closers   []io.Closer
+}
+
+// newApp creates the evaluator from the common flags, which must already be parsed
+func newApp(extra ...aggregdepscore.EvaluatorOption) (*app, error) {
+	a := &app{}
+
+	logger, err := newLogger(*logLevel, *logFormat)
 	if err != nil {
-		return fmt.Errorf("evaluating score: %w", err)
+		flag.Usage()
+		return nil, &flagError{err: fmt.Errorf("validating flags: %w", err)}
 	}
+	a.logger = logger
 
-	fmt.Println(score)
+	selector, err := repositorySelector(*repoSelect)
+	if err != nil {
+		flag.Usage()
+		return nil, &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
 
-	return nil
+	a.scope, err = newScope(*scopeEcosystems, *scopeExclude)
+	if err != nil {
+		flag.Usage()
+		return nil, &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
+
+	parameters := modelParameters()
+
+	depsDotDevOptions := []aggregdepscore.DepsDotDevOption{
+		aggregdepscore.WithRepositorySelector(selector),
+		aggregdepscore.WithDepsDotDevLogger(logger),
+		aggregdepscore.WithDepsDotDevConverter(parameters.Converter()),
+	}
+
+	if *retries != 0 {
+		depsDotDevOptions = append(depsDotDevOptions, aggregdepscore.WithDepsDotDevRetry(aggregdepscore.RetryPolicy{
+			MaxAttempts:    *retries + 1,
+			InitialBackoff: *backoff,
+			MaxBackoff:     maxRetryBackoff,
+		}))
+	}
+
+	if *rateLimit != 0 {
+		depsDotDevOptions = append(depsDotDevOptions, aggregdepscore.WithDepsDotDevRateLimit(*rateLimit))
+	}
+
+	if *goVanity {
+		depsDotDevOptions = append(depsDotDevOptions, aggregdepscore.WithGoVanityResolver(
+			aggregdepscore.NewGoVanityResolver(&aggregdepscore.HTTPGoImportFetcher{}),
+		))
+	}
+
+	depsdotdev, err := aggregdepscore.NewDepsDotDevClient(depsDotDevOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("creating deps.dev client: %w", err)
+	}
+
+	var intrinsic aggregdepscore.IntrinsicTrustworthinessEvaluator = depsdotdev
+	var deps aggregdepscore.DependencyResolver = depsdotdev
+
+	o, err := loadOverrides()
+	if err != nil {
+		return nil, fmt.Errorf("loading overrides: %w", err)
+	}
+
+	if o != nil {
+		layer, err := aggregdepscore.NewOverrideLayer(o, depsdotdev, depsdotdev)
+		if err != nil {
+			return nil, fmt.Errorf("creating override layer: %w", err)
+		}
+
+		intrinsic, deps = layer, layer
+	}
+
+	options := []aggregdepscore.EvaluatorOption{
+		aggregdepscore.WithLogger(logger),
+		aggregdepscore.WithParameters(parameters),
+	}
+
+	if *trustDomain != "" {
+		policy, err := trustDomainPolicy(*trustDomain)
+		if err != nil {
+			flag.Usage()
+			return nil, &flagError{err: fmt.Errorf("validating flags: %w", err)}
+		}
+
+		options = append(options, aggregdepscore.WithTrustDomainGrouping(policy))
+	}
+
+	if *fallback >= 0 {
+		options = append(options, aggregdepscore.WithIntrinsicFallback(*fallback))
+	}
+
+	if *dogstatsd != "" {
+		emitter, err := aggregdepscore.NewDogStatsDEmitter(*dogstatsd)
+		if err != nil {
+			return nil, fmt.Errorf("creating DogStatsD emitter: %w", err)
+		}
+		a.closers = append(a.closers, emitter)
+
+		options = append(options, aggregdepscore.WithScoreEmitter(emitter))
+	}
+
+	if *maxDepth != 0 || *maxNodes != 0 || *maxCalls != 0 {
+		policy, err := truncationPolicy(*truncation)
+		if err != nil {
+			flag.Usage()
+			return nil, &flagError{err: fmt.Errorf("validating flags: %w", err)}
+		}
+
+		options = append(options, aggregdepscore.WithLimits(aggregdepscore.Limits{
+			MaxDepth:               *maxDepth,
+			MaxNodes:               *maxNodes,
+			MaxCalls:               *maxCalls,
+			Policy:                 policy,
+			AssumedTrustworthiness: *assumed,
+		}))
+	}
+
+	if *progress && isTerminal(os.Stderr) {
+		a.progress = &progressLine{output: os.Stderr}
+		options = append(options, aggregdepscore.WithObserver(a.progress))
+	}
+
+	options = append(options, extra...)
+
+	a.evaluator, err = aggregdepscore.NewEvaluator(intrinsic, deps, options...)
+	if err != nil {
+		return nil, fmt.Errorf("creating evaluator: %w", err)
+	}
+
+	return a, nil
+}
+
+// modelParameters returns the constants of the model set by flags
+func modelParameters() aggregdepscore.Parameters {
+	return aggregdepscore.Parameters{
+		TransitiveTrustworthinessExponent: *exponent,
+		MinTrustworthiness:                *minTrust,
+		TrustworthinessOffset:             *trustOffset,
+	}
+}
+
+// loadOverrides returns the overrides of the --overrides file,
+// or else the inline overrides of the configuration file, or nil without overrides
+func loadOverrides() (*aggregdepscore.Overrides, error) {
+	if *overrides != "" {
+		return aggregdepscore.LoadOverrides(*overrides)
+	}
+
+	if config != nil && len(config.Overrides) > 0 {
+		return &aggregdepscore.Overrides{Rules: config.Overrides}, nil
+	}
+
+	return nil, nil
+}
+
+// evaluate evaluates p, clearing the progress line (if any) once done
+func (a *app) evaluate(ctx context.Context, p aggregdepscore.Package) (*aggregdepscore.Evaluation, error) {
+	evaluation, err := a.evaluator.Evaluate(ctx, p)
+	if a.progress != nil {
+		a.progress.clear()
+	}
+
+	return evaluation, err
+}
+
+func (a *app) close() {
+	for _, c := range a.closers {
+		err := c.Close()
+		if err != nil {
+			a.logger.Warn("closing", slog.String("error", err.Error()))
+		}
+	}
 }
 
 func validateFlags() error {
+	err := validatePackageFlags()
+	if err != nil {
+		return err
+	}
+
+	return validateFormat(*format)
+}
+
+// validatePackageFlags validates the flags of the evaluated package
+func validatePackageFlags() error {
 	if *ecosystem == "" {
 		return fmt.Errorf("ecosystem is required")
 	}
@@ -68,3 +334,63 @@ func validateFlags() error {
 
 	return nil
 }
+
+func repositorySelector(name string) (aggregdepscore.RepositorySelector, error) {
+	switch name {
+	case "verified":
+		return &aggregdepscore.VerifiedRepositorySelector{
+			Fallback: &aggregdepscore.LowestScoreRepositorySelector{},
+		}, nil
+	case "first":
+		return &aggregdepscore.FirstRepositorySelector{}, nil
+	case "lowest":
+		return &aggregdepscore.LowestScoreRepositorySelector{}, nil
+	case "highest":
+		return &aggregdepscore.HighestScoreRepositorySelector{}, nil
+	default:
+		return nil, fmt.Errorf("unknown repository selection strategy: %q", name)
+	}
+}
+
+func truncationPolicy(name string) (aggregdepscore.TruncationPolicy, error) {
+	switch name {
+	case "fail":
+		return aggregdepscore.TruncateFail, nil
+	case "assume":
+		return aggregdepscore.TruncateAssumeTrustworthiness, nil
+	case "intrinsic-only":
+		return aggregdepscore.TruncateIntrinsicOnly, nil
+	default:
+		return 0, fmt.Errorf("unknown truncation policy: %q", name)
+	}
+}
+
+func trustDomainPolicy(name string) (aggregdepscore.TrustDomainPolicy, error) {
+	switch name {
+	case "worst":
+		return &aggregdepscore.WorstMemberPolicy{}, nil
+	case "geometric-mean":
+		return &aggregdepscore.GeometricMeanPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown trust domain policy: %q", name)
+	}
+}
+
+func newLogger(level string, format string) (*slog.Logger, error) {
+	var l slog.Level
+	err := l.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	options := &slog.HandlerOptions{Level: l}
+
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %q", format)
+	}
+}
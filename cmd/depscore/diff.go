@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// runDiff compares two versions of a project,
+// given either as two paths or as git revisions of one path
+func runDiff() error {
+	oldRevision := flag.String("old-ref", "", "Git revision of the old version of the project, which is then read from the repository")
+	newRevision := flag.String("new-ref", "", "Git revision of the new version of the project, with -old-ref (the working tree if empty)")
+	maxEdges := flag.Int("edges", 10, "Number of edges listed in text and markdown outputs (all if 0)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %[1]s diff [flags] <old path> <new path>\n  %[1]s diff -old-ref <revision> [-new-ref <revision>] [flags] [path]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	before, after, err := diffSources(*oldRevision, *newRevision, flag.Args())
+	if err == nil {
+		err = validateDiffFormat(*format)
+	}
+	if err != nil {
+		flag.Usage()
+		return &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
+
+	a, err := newApp(aggregdepscore.WithEvaluationTree())
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	ctx := context.Background()
+
+	beforeEvaluation, beforeResult, err := a.evaluateSource(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	afterEvaluation, afterResult, err := a.evaluateSource(ctx, after)
+	if err != nil {
+		return err
+	}
+
+	edges, err := aggregdepscore.DiffEvaluations(beforeEvaluation, afterEvaluation)
+	if err != nil {
+		return fmt.Errorf("diffing evaluations: %w", err)
+	}
+
+	d := newDiffResult(beforeResult, afterResult, edges)
+
+	err = writeDiff(os.Stdout, *format, d, *maxEdges)
+	if err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+
+	return checkGates(os.Stderr, []result{afterResult})
+}
+
+// projectSource is where the files of a version of a project are read from
+type projectSource struct {
+	fsys fs.FS
+	// path of the project (a directory or a supported file) in fsys
+	path string
+	// name of the project
+	name string
+	// description of the source, for messages
+	description string
+}
+
+// diffSources returns the sources of the old and new versions of the project from the diff flags and arguments
+func diffSources(oldRevision string, newRevision string, args []string) (projectSource, projectSource, error) {
+	if oldRevision == "" {
+		if newRevision != "" {
+			return projectSource{}, projectSource{}, fmt.Errorf("-new-ref requires -old-ref")
+		}
+		if len(args) != 2 {
+			return projectSource{}, projectSource{}, fmt.Errorf("expected the paths of the old and new versions of the project")
+		}
+
+		before, err := localSource(args[0])
+		if err != nil {
+			return projectSource{}, projectSource{}, err
+		}
+
+		after, err := localSource(args[1])
+		return before, after, err
+	}
+
+	if len(args) > 1 {
+		return projectSource{}, projectSource{}, fmt.Errorf("expected at most one path with -old-ref")
+	}
+
+	p := "."
+	if len(args) == 1 {
+		p = args[0]
+	}
+
+	before, err := gitSource(oldRevision, p)
+	if err != nil {
+		return projectSource{}, projectSource{}, err
+	}
+
+	if newRevision == "" {
+		after, err := localSource(p)
+		return before, after, err
+	}
+
+	after, err := gitSource(newRevision, p)
+	return before, after, err
+}
+
+func localSource(p string) (projectSource, error) {
+	absolute, err := filepath.Abs(p)
+	if err != nil {
+		return projectSource{}, err
+	}
+
+	return projectSource{
+		fsys:        os.DirFS(filepath.Dir(absolute)),
+		path:        filepath.Base(absolute),
+		name:        projectName(absolute),
+		description: p,
+	}, nil
+}
+
+func gitSource(revision string, p string) (projectSource, error) {
+	absolute, err := filepath.Abs(p)
+	if err != nil {
+		return projectSource{}, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return projectSource{}, err
+	}
+
+	relative, err := filepath.Rel(wd, absolute)
+	if err != nil {
+		return projectSource{}, err
+	}
+
+	relative = filepath.ToSlash(relative)
+	if !fs.ValidPath(relative) {
+		return projectSource{}, fmt.Errorf("path %s must be in the current directory with -old-ref", p)
+	}
+
+	fsys, err := newGitFS(revision)
+	if err != nil {
+		return projectSource{}, err
+	}
+
+	return projectSource{
+		fsys:        fsys,
+		path:        relative,
+		name:        projectName(absolute),
+		description: revision + ":" + relative,
+	}, nil
+}
+
+// evaluateSource evaluates the version of the project in source
+func (a *app) evaluateSource(ctx context.Context, source projectSource) (*aggregdepscore.Evaluation, result, error) {
+	start := time.Now()
+
+	files, err := readProject(source.fsys, source.path)
+	if err != nil {
+		return nil, result{}, fmt.Errorf("reading dependencies of %s: %w", source.description, err)
+	}
+
+	evaluation, err := a.evaluateFiles(ctx, source.name, files)
+	if err != nil {
+		return nil, result{}, fmt.Errorf("%s: %w", source.description, err)
+	}
+
+	return evaluation, newProjectResult(evaluation, files, time.Since(start)), nil
+}
+
+// diffResult is the comparison of two versions of a project, as output by depscore diff
+type diffResult struct {
+	Before     result             `json:"before"`
+	After      result             `json:"after"`
+	ScoreDelta float64            `json:"score_delta"`
+	Added      []dependencyOutput `json:"added"`
+	Removed    []dependencyOutput `json:"removed"`
+	// Upgraded are the direct dependencies whose version changed, downgrades included
+	Upgraded []upgradeOutput `json:"upgraded"`
+	// Edges are the edges of the dependency graph the change of score is attributed to,
+	// the most significant first
+	Edges []edgeOutput `json:"edges"`
+}
+
+type upgradeOutput struct {
+	Before dependencyOutput `json:"before"`
+	After  dependencyOutput `json:"after"`
+}
+
+type edgeOutput struct {
+	Parent  packageOutput  `json:"parent"`
+	Before  *packageOutput `json:"before"`
+	After   *packageOutput `json:"after"`
+	Depth   int            `json:"depth"`
+	NbPaths int            `json:"nb_paths"`
+	// LogContribution is the contribution of the edge to the change of the log of the aggregated trustworthiness,
+	// and Factor the factor it multiplies the aggregated trustworthiness by
+	LogContribution *float64 `json:"log_contribution"`
+	Factor          *float64 `json:"factor"`
+}
+
+func newDiffResult(before result, after result, edges []aggregdepscore.EdgeChange) diffResult {
+	d := diffResult{
+		Before:     before,
+		After:      after,
+		ScoreDelta: after.Score - before.Score,
+		Added:      []dependencyOutput{},
+		Removed:    []dependencyOutput{},
+		Upgraded:   []upgradeOutput{},
+		Edges:      []edgeOutput{},
+	}
+
+	beforeByKey := make(map[string]dependencyOutput)
+	for _, dep := range before.Dependencies {
+		beforeByKey[packageKey(dep.Package)] = dep
+	}
+
+	afterKeys := make(map[string]bool)
+	for _, dep := range after.Dependencies {
+		afterKeys[packageKey(dep.Package)] = true
+
+		previous, ok := beforeByKey[packageKey(dep.Package)]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, dep)
+		case previous.Package.Version != dep.Package.Version:
+			d.Upgraded = append(d.Upgraded, upgradeOutput{Before: previous, After: dep})
+		}
+	}
+
+	for _, dep := range before.Dependencies {
+		if !afterKeys[packageKey(dep.Package)] {
+			d.Removed = append(d.Removed, dep)
+		}
+	}
+
+	// the most significant first, whether they lower or raise the trustworthiness
+	edges = append([]aggregdepscore.EdgeChange(nil), edges...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		return math.Abs(edges[i].LogContribution) > math.Abs(edges[j].LogContribution)
+	})
+
+	for _, edge := range edges {
+		e := edgeOutput{
+			Parent:          newPackageOutput(edge.Parent),
+			Depth:           edge.Depth,
+			NbPaths:         edge.NbPaths,
+			LogContribution: finite(edge.LogContribution),
+			Factor:          finite(math.Exp(edge.LogContribution)),
+		}
+		if edge.Before != nil {
+			p := newPackageOutput(*edge.Before)
+			e.Before = &p
+		}
+		if edge.After != nil {
+			p := newPackageOutput(*edge.After)
+			e.After = &p
+		}
+
+		d.Edges = append(d.Edges, e)
+	}
+
+	return d
+}
+
+func validateDiffFormat(format string) error {
+	switch format {
+	case "text", "json", "markdown":
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for diff: %q", format)
+	}
+}
+
+// writeDiff writes d, listing at most maxEdges edges in text and markdown (all if 0)
+func writeDiff(w io.Writer, format string, d diffResult, maxEdges int) error {
+	edges := d.Edges
+	if maxEdges > 0 && len(edges) > maxEdges {
+		edges = edges[:maxEdges]
+	}
+
+	var b strings.Builder
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	case "text":
+		fmt.Fprintf(&b, "score %.3f -> %.3f (%+.3f)\n", d.Before.Score, d.After.Score, d.ScoreDelta)
+		for _, dep := range d.Added {
+			fmt.Fprintf(&b, "added    %s: score %.3f, aggregated trustworthiness %.3g\n", describePackage(dep.Package), dep.Score, dep.AggregatedTrustworthiness)
+		}
+		for _, dep := range d.Removed {
+			fmt.Fprintf(&b, "removed  %s: score %.3f, aggregated trustworthiness %.3g\n", describePackage(dep.Package), dep.Score, dep.AggregatedTrustworthiness)
+		}
+		for _, u := range d.Upgraded {
+			fmt.Fprintf(&b, "upgraded %s -> %s: score %.3f -> %.3f, aggregated trustworthiness %.3g -> %.3g\n",
+				describePackage(u.Before.Package), u.After.Package.Version, u.Before.Score, u.After.Score,
+				u.Before.AggregatedTrustworthiness, u.After.AggregatedTrustworthiness)
+		}
+		if len(edges) > 0 {
+			b.WriteString("edges the change is attributed to (factor of the aggregated trustworthiness):\n")
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&b, "  %s %s\n", formatFactor(e.Factor), describeEdge(e))
+		}
+		if len(edges) < len(d.Edges) {
+			fmt.Fprintf(&b, "  … %d more edges\n", len(d.Edges)-len(edges))
+		}
+	case "markdown":
+		fmt.Fprintf(&b, "**%s**: score %.3f → %.3f (%+.3f)\n", escapeMarkdown(d.After.Package.Name), d.Before.Score, d.After.Score, d.ScoreDelta)
+		if len(d.Added)+len(d.Removed)+len(d.Upgraded) > 0 {
+			b.WriteString("\n| Change | Ecosystem | Dependency | Version | Score | Aggregated trustworthiness |\n")
+			b.WriteString("|---|---|---|---|---:|---:|\n")
+		}
+		for _, dep := range d.Added {
+			fmt.Fprintf(&b, "| added | %s | %s | %s | %.3f | %.3g |\n",
+				escapeMarkdown(dep.Package.Ecosystem), escapeMarkdown(dep.Package.Name), escapeMarkdown(dep.Package.Version), dep.Score, dep.AggregatedTrustworthiness)
+		}
+		for _, dep := range d.Removed {
+			fmt.Fprintf(&b, "| removed | %s | %s | %s | %.3f | %.3g |\n",
+				escapeMarkdown(dep.Package.Ecosystem), escapeMarkdown(dep.Package.Name), escapeMarkdown(dep.Package.Version), dep.Score, dep.AggregatedTrustworthiness)
+		}
+		for _, u := range d.Upgraded {
+			fmt.Fprintf(&b, "| upgraded | %s | %s | %s → %s | %.3f → %.3f | %.3g → %.3g |\n",
+				escapeMarkdown(u.After.Package.Ecosystem), escapeMarkdown(u.After.Package.Name),
+				escapeMarkdown(u.Before.Package.Version), escapeMarkdown(u.After.Package.Version),
+				u.Before.Score, u.After.Score, u.Before.AggregatedTrustworthiness, u.After.AggregatedTrustworthiness)
+		}
+		if len(edges) > 0 {
+			b.WriteString("\n| Factor | Edge |\n")
+			b.WriteString("|---:|---|\n")
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&b, "| %s | %s |\n", formatFactor(e.Factor), escapeMarkdown(describeEdge(e)))
+		}
+		if len(edges) < len(d.Edges) {
+			fmt.Fprintf(&b, "\n%d more edges are not listed.\n", len(d.Edges)-len(edges))
+		}
+	default:
+		return fmt.Errorf("unsupported output format for diff: %q", format)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatFactor formats the factor of an edge, which is nil when infinite
+func formatFactor(factor *float64) string {
+	if factor == nil {
+		return "×∞"
+	}
+
+	return fmt.Sprintf("×%.3f", *factor)
+}
+
+func describeEdge(e edgeOutput) string {
+	switch {
+	case e.Before == nil:
+		return fmt.Sprintf("%s → %s (added)", describePackage(e.Parent), describePackage(*e.After))
+	case e.After == nil:
+		return fmt.Sprintf("%s → %s (removed)", describePackage(e.Parent), describePackage(*e.Before))
+	case e.Before.Version != e.After.Version:
+		return fmt.Sprintf("%s → %s (from %s)", describePackage(e.Parent), describePackage(*e.After), e.Before.Version)
+	default:
+		return fmt.Sprintf("%s → %s", describePackage(e.Parent), describePackage(*e.After))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// runExplain prints the tree of evaluated packages,
+// showing how each dependency lowers the aggregated trustworthiness of its parent
+func runExplain() error {
+	maxDepth := flag.Int("depth", 3, "Depth beyond which dependencies are collapsed (unlimited if 0)")
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	err = validateFlags()
+	if err != nil {
+		flag.Usage()
+		return &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
+
+	a, err := newApp(aggregdepscore.WithEvaluationTree())
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	evaluation, err := a.evaluate(context.Background(), packageFromFlags())
+	if err != nil {
+		return fmt.Errorf("evaluating score: %w", err)
+	}
+
+	evaluation.Tree.SortWorstFirst()
+
+	fmt.Fprintf(os.Stdout, "score %.3f\n", evaluation.Score)
+	return writeTree(os.Stdout, evaluation.Tree, *maxDepth)
+}
+
+// writeTree writes root and its descendants up to maxDepth, one per line
+func writeTree(w io.Writer, root *aggregdepscore.Node, maxDepth int) error {
+	var b strings.Builder
+
+	b.WriteString(describeNode(root))
+	b.WriteByte('\n')
+	writeChildren(&b, root, "", maxDepth)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeChildren(b *strings.Builder, node *aggregdepscore.Node, prefix string, maxDepth int) {
+	if maxDepth > 0 && node.Depth >= maxDepth && len(node.Children) > 0 {
+		nbNodes := 0
+		for _, child := range node.Children {
+			child.Walk(func(*aggregdepscore.Node) bool {
+				nbNodes++
+				return true
+			})
+		}
+
+		fmt.Fprintf(b, "%s└─ … %d dependencies collapsed (%d nodes)\n", prefix, len(node.Children), nbNodes)
+		return
+	}
+
+	for i, child := range node.Children {
+		branch, indent := "├─ ", "│  "
+		if i == len(node.Children)-1 {
+			branch, indent = "└─ ", "   "
+		}
+
+		b.WriteString(prefix)
+		b.WriteString(branch)
+		b.WriteString(describeNode(child))
+		b.WriteByte('\n')
+
+		writeChildren(b, child, prefix+indent, maxDepth)
+	}
+}
+
+func describeNode(n *aggregdepscore.Node) string {
+	var b strings.Builder
+
+	if n.Depth == 0 {
+		fmt.Fprintf(&b, "%s ", n.Package.Ecosystem)
+	}
+	b.WriteString(n.Package.Name)
+	if n.Package.Version != "" {
+		b.WriteString("@" + n.Package.Version)
+	}
+
+	if n.Cycle != nil {
+		fmt.Fprintf(&b, "  [cycle cut: %d packages]", len(n.Cycle.Packages)-1)
+		return b.String()
+	}
+
+	if n.Truncation == nil || n.Truncation.Policy != aggregdepscore.TruncateAssumeTrustworthiness {
+		fmt.Fprintf(&b, "  intrinsic %.3f", n.IntrinsicTrustworthiness)
+	}
+	fmt.Fprintf(&b, "  aggregated %.3g", n.AggregatedTrustworthiness)
+
+	if n.Depth > 0 {
+		fmt.Fprintf(&b, "  penalty ×%.3g", n.Penalty)
+	}
+
+	if n.Fallback != nil {
+		fmt.Fprintf(&b, "  [fallback: %s]", n.Fallback.Reason)
+	}
+
+	if n.Truncation != nil {
+		fmt.Fprintf(&b, "  [truncated: %s limit, %s]", n.Truncation.Limit, n.Truncation.Policy)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	minScore            = flag.Float64("min-score", -1, "Fail with exit code 3 if a ranking score is below this value (disabled if negative)")
+	baseline            = flag.String("baseline", "", "JSON output of a previous run; fail with exit code 4 if a ranking score is lower than in it (optional)")
+	regressionTolerance = flag.Float64("regression-tolerance", 0, "Score decrease versus the baseline that is not considered a regression")
+)
+
+// Exit codes of depscore; flag.Parse exits with exitInvalidFlags on its own
+const (
+	exitEvaluationError = 1
+	exitInvalidFlags    = 2
+	exitBelowThreshold  = 3
+	exitRegression      = 4
+)
+
+// gateError is returned when results do not pass the checks set by flags
+type gateError struct {
+	code    int
+	message string
+}
+
+func (e *gateError) Error() string {
+	return e.message
+}
+
+// flagError is returned when flags, their environment variables or the configuration file are invalid
+type flagError struct {
+	err error
+}
+
+func (e *flagError) Error() string {
+	return e.err.Error()
+}
+
+func (e *flagError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the exit code corresponding to err
+func exitCode(err error) int {
+	var g *gateError
+	if errors.As(err, &g) {
+		return g.code
+	}
+
+	var f *flagError
+	if errors.As(err, &f) {
+		return exitInvalidFlags
+	}
+
+	return exitEvaluationError
+}
+
+// checkGates checks results against --min-score and --baseline,
+// writing a summary of the failures to w.
+// Ranking scores are compared, so that packages that got worse are caught
+// even when their score is clamped at zero.
+func checkGates(w io.Writer, results []result) error {
+	var below []result
+	if *minScore >= 0 {
+		for _, r := range results {
+			if ranking(r.Score, r.RankingScore) < *minScore {
+				below = append(below, r)
+			}
+		}
+	}
+
+	var regressions []regression
+	if *baseline != "" {
+		previous, err := loadBaseline(*baseline)
+		if err != nil {
+			return fmt.Errorf("loading baseline: %w", err)
+		}
+
+		regressions = findRegressions(previous, results, *regressionTolerance)
+	}
+
+	for _, r := range below {
+		fmt.Fprintf(w, "%s is below the minimum score: %.4f < %.4f\n", describePackage(r.Package), ranking(r.Score, r.RankingScore), *minScore)
+	}
+
+	for _, reg := range regressions {
+		fmt.Fprintf(w, "%s regressed versus the baseline: %.4f -> %.4f\n", describePackage(reg.pkg), reg.before, reg.after)
+		for _, cause := range reg.causes {
+			fmt.Fprintf(w, "  %s\n", cause)
+		}
+	}
+
+	switch {
+	case len(below) > 0:
+		return &gateError{code: exitBelowThreshold, message: fmt.Sprintf("score below %g for %d packages", *minScore, len(below))}
+	case len(regressions) > 0:
+		return &gateError{code: exitRegression, message: fmt.Sprintf("score regressed versus the baseline for %d packages", len(regressions))}
+	default:
+		return nil
+	}
+}
+
+// validateThresholds validates --min-score, --low-trust and --regression-tolerance,
+// scores being between 0 and 1
+func validateThresholds() error {
+	if *minScore > 1 {
+		return fmt.Errorf("min score must be at most 1, got %g", *minScore)
+	}
+
+	if *lowTrust < 0 || *lowTrust > 1 {
+		return fmt.Errorf("low trust must be between 0 and 1, got %g", *lowTrust)
+	}
+
+	if *regressionTolerance < 0 {
+		return fmt.Errorf("regression tolerance must be positive, got %g", *regressionTolerance)
+	}
+
+	return nil
+}
+
+// loadBaseline reads the JSON output of depscore, either a single result or an array of results
+func loadBaseline(filename string) ([]result, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []result
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		err = json.Unmarshal(content, &results)
+	} else {
+		var r result
+		err = json.Unmarshal(content, &r)
+		results = []result{r}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	return results, nil
+}
+
+// ranking returns a ranking score, or the score for baselines written without ranking scores
+func ranking(score float64, rankingScore *float64) float64 {
+	if rankingScore == nil {
+		return score
+	}
+
+	return *rankingScore
+}
+
+// logTrustworthiness returns the log aggregated trustworthiness of dep,
+// computed from its aggregated trustworthiness for baselines written without it
+func logTrustworthiness(dep dependencyOutput) float64 {
+	if dep.LogAggregatedTrustworthiness == nil {
+		return math.Log(dep.AggregatedTrustworthiness)
+	}
+
+	return *dep.LogAggregatedTrustworthiness
+}
+
+type regression struct {
+	pkg    packageOutput
+	before float64
+	after  float64
+	// causes describe the direct dependencies that were added or got a lower score
+	causes []string
+}
+
+// packageKey identifies a package across versions, so that upgrades can be compared
+func packageKey(p packageOutput) string {
+	return p.Ecosystem + "/" + p.Name
+}
+
+func findRegressions(previous []result, current []result, tolerance float64) []regression {
+	previousByKey := make(map[string]result)
+	for _, r := range previous {
+		previousByKey[packageKey(r.Package)] = r
+	}
+
+	var regressions []regression
+	for _, r := range current {
+		previous, ok := previousByKey[packageKey(r.Package)]
+		if !ok {
+			continue
+		}
+
+		before, after := ranking(previous.Score, previous.RankingScore), ranking(r.Score, r.RankingScore)
+		if after >= before-tolerance {
+			continue
+		}
+
+		regressions = append(regressions, regression{
+			pkg:    r.Package,
+			before: before,
+			after:  after,
+			causes: regressionCauses(previous.Dependencies, r.Dependencies, tolerance),
+		})
+	}
+
+	return regressions
+}
+
+// regressionCauses lists the direct dependencies that were added or got a lower ranking score,
+// those lowering the trustworthiness of the package the most first
+// (an added dependency lowers it by its own trustworthiness)
+func regressionCauses(before []dependencyOutput, after []dependencyOutput, tolerance float64) []string {
+	beforeByKey := make(map[string]dependencyOutput)
+	for _, dep := range before {
+		beforeByKey[packageKey(dep.Package)] = dep
+	}
+
+	type cause struct {
+		description string
+		// logDecrease is the decrease of the log trustworthiness of the dependency,
+		// which the log trustworthiness of the package decreases proportionally to
+		logDecrease float64
+	}
+
+	var causes []cause
+	for _, dep := range after {
+		previous, ok := beforeByKey[packageKey(dep.Package)]
+		if !ok {
+			causes = append(causes, cause{
+				description: fmt.Sprintf("added %s with score %.4f", describePackage(dep.Package), ranking(dep.Score, dep.RankingScore)),
+				logDecrease: -logTrustworthiness(dep),
+			})
+			continue
+		}
+
+		previousRanking, currentRanking := ranking(previous.Score, previous.RankingScore), ranking(dep.Score, dep.RankingScore)
+		if currentRanking < previousRanking-tolerance {
+			description := fmt.Sprintf("%s: %.4f -> %.4f", describePackage(dep.Package), previousRanking, currentRanking)
+			if previous.Package.Version != dep.Package.Version {
+				description += fmt.Sprintf(" (version %s -> %s)", previous.Package.Version, dep.Package.Version)
+			}
+			causes = append(causes, cause{description: description, logDecrease: logTrustworthiness(previous) - logTrustworthiness(dep)})
+		}
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool { return causes[i].logDecrease > causes[j].logDecrease })
+
+	var result []string
+	for _, c := range causes {
+		result = append(result, c.description)
+	}
+
+	return result
+}
+
+func describePackage(p packageOutput) string {
+	s := p.Name
+	if p.Ecosystem != "" {
+		s = p.Ecosystem + " " + s
+	}
+	if p.Version != "" {
+		s += "@" + p.Version
+	}
+
+	return s
+}
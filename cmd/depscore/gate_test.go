@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// testResult is a result with a ranking score, its score being the ranking score clamped at zero
+func testResult(p packageOutput, rankingScore float64) result {
+	return result{Package: p, Score: math.Max(rankingScore, 0), RankingScore: &rankingScore}
+}
+
+// testDependency is a direct dependency with a ranking score and a log aggregated trustworthiness
+func testDependency(name string, version string, rankingScore float64, logTrustworthiness float64) dependencyOutput {
+	return dependencyOutput{
+		Package:                      packageOutput{Ecosystem: "npm", Name: name, Version: version},
+		Score:                        math.Max(rankingScore, 0),
+		RankingScore:                 &rankingScore,
+		AggregatedTrustworthiness:    math.Exp(logTrustworthiness),
+		LogAggregatedTrustworthiness: &logTrustworthiness,
+	}
+}
+
+func TestFindRegressions(t *testing.T) {
+	express := packageOutput{Ecosystem: "npm", Name: "express", Version: "4.18.2"}
+	expressUpgraded := packageOutput{Ecosystem: "npm", Name: "express", Version: "4.19.0"}
+	requests := packageOutput{Ecosystem: "pypi", Name: "requests", Version: "2.28.1"}
+	lodash := packageOutput{Ecosystem: "npm", Name: "lodash", Version: "4.17.21"}
+
+	previous := []result{
+		testResult(express, 0.5),
+		testResult(requests, 0.75),
+		testResult(lodash, -1),
+	}
+
+	testCases := []struct {
+		name      string
+		previous  []result
+		current   []result
+		tolerance float64
+		expected  []string
+	}{
+		{
+			name:     "same scores",
+			current:  []result{testResult(express, 0.5), testResult(requests, 0.75)},
+			expected: nil,
+		},
+		{
+			name:     "higher score",
+			current:  []result{testResult(express, 0.75)},
+			expected: nil,
+		},
+		{
+			name:     "lower score",
+			current:  []result{testResult(express, 0.25), testResult(requests, 0.75)},
+			expected: []string{"npm express@4.18.2 0.5 -> 0.25"},
+		},
+		{
+			name:     "lower score of an upgraded package",
+			current:  []result{testResult(expressUpgraded, 0.25)},
+			expected: []string{"npm express@4.19.0 0.5 -> 0.25"},
+		},
+		{
+			name:     "lower score clamped at zero",
+			current:  []result{testResult(lodash, -3)},
+			expected: []string{"npm lodash@4.17.21 -1 -> -3"},
+		},
+		{
+			name:     "package not in the baseline",
+			current:  []result{testResult(packageOutput{Ecosystem: "npm", Name: "debug", Version: "2.6.9"}, 0)},
+			expected: nil,
+		},
+		{
+			name:      "decrease within the tolerance",
+			current:   []result{testResult(express, 0.25)},
+			tolerance: 0.25,
+			expected:  nil,
+		},
+		{
+			name:      "decrease beyond the tolerance",
+			current:   []result{testResult(express, 0.25)},
+			tolerance: 0.125,
+			expected:  []string{"npm express@4.18.2 0.5 -> 0.25"},
+		},
+		{
+			name:     "baseline without ranking scores",
+			previous: []result{{Package: express, Score: 0.5}},
+			current:  []result{testResult(express, -1)},
+			expected: []string{"npm express@4.18.2 0.5 -> -1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseline := previous
+			if tc.previous != nil {
+				baseline = tc.previous
+			}
+
+			var actual []string
+			for _, reg := range findRegressions(baseline, tc.current, tc.tolerance) {
+				actual = append(actual, fmt.Sprintf("%s %g -> %g", describePackage(reg.pkg), reg.before, reg.after))
+			}
+
+			if strings.Join(actual, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected regressions %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRegressionCauses(t *testing.T) {
+	before := []dependencyOutput{
+		testDependency("body-parser", "1.20.1", 0.75, -0.05),
+		testDependency("cookie", "0.5.0", 0.5, -0.1),
+		testDependency("ms", "2.0.0", -1, -0.45),
+	}
+
+	testCases := []struct {
+		name      string
+		after     []dependencyOutput
+		tolerance float64
+		expected  []string
+	}{
+		{
+			name:     "unchanged dependencies",
+			after:    before,
+			expected: nil,
+		},
+		{
+			name:     "added dependency",
+			after:    append(before[:3:3], testDependency("debug", "2.6.9", 0.25, -0.15)),
+			expected: []string{"added npm debug@2.6.9 with score 0.2500"},
+		},
+		{
+			name:     "removed dependency",
+			after:    before[:1],
+			expected: nil,
+		},
+		{
+			name:     "lower score",
+			after:    []dependencyOutput{testDependency("body-parser", "1.20.1", 0.5, -0.1), before[1], before[2]},
+			expected: []string{"npm body-parser@1.20.1: 0.7500 -> 0.5000"},
+		},
+		{
+			name:     "lower score clamped at zero",
+			after:    []dependencyOutput{before[0], before[1], testDependency("ms", "2.0.0", -3, -0.9)},
+			expected: []string{"npm ms@2.0.0: -1.0000 -> -3.0000"},
+		},
+		{
+			name:     "version change",
+			after:    []dependencyOutput{before[0], testDependency("cookie", "0.6.0", 0.25, -0.15), before[2]},
+			expected: []string{"npm cookie@0.6.0: 0.5000 -> 0.2500 (version 0.5.0 -> 0.6.0)"},
+		},
+		{
+			name: "largest decreases of trustworthiness first",
+			after: []dependencyOutput{
+				testDependency("body-parser", "1.20.1", 0.625, -0.075),
+				testDependency("cookie", "0.5.0", 0.25, -0.15),
+				testDependency("ms", "2.0.0", -3, -0.9),
+				testDependency("debug", "2.6.9", 0, math.Log(0.8)),
+			},
+			expected: []string{
+				"npm ms@2.0.0: -1.0000 -> -3.0000",
+				"added npm debug@2.6.9 with score 0.0000",
+				"npm cookie@0.5.0: 0.5000 -> 0.2500",
+				"npm body-parser@1.20.1: 0.7500 -> 0.6250",
+			},
+		},
+		{
+			name:      "decrease within the tolerance",
+			after:     []dependencyOutput{testDependency("body-parser", "1.20.1", 0.5, -0.1), testDependency("cookie", "0.5.0", 0.25, -0.15), before[2]},
+			tolerance: 0.25,
+			expected:  nil,
+		},
+		{
+			name:      "decrease beyond the tolerance",
+			after:     []dependencyOutput{testDependency("body-parser", "1.20.1", 0.5, -0.1), testDependency("cookie", "0.5.0", 0.375, -0.125), before[2]},
+			tolerance: 0.125,
+			expected:  []string{"npm body-parser@1.20.1: 0.7500 -> 0.5000"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := regressionCauses(before, tc.after, tc.tolerance)
+			if strings.Join(actual, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected causes %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "evaluation error", err: errors.New("deps.dev is unavailable"), expected: exitEvaluationError},
+		{name: "invalid flags", err: &flagError{err: errors.New("validating flags: unknown output format")}, expected: exitInvalidFlags},
+		{name: "below threshold", err: &gateError{code: exitBelowThreshold, message: "score below 0.5 for 1 packages"}, expected: exitBelowThreshold},
+		{name: "regression", err: &gateError{code: exitRegression, message: "score regressed versus the baseline for 1 packages"}, expected: exitRegression},
+		{name: "wrapped gate error", err: fmt.Errorf("checking results: %w", &gateError{code: exitRegression}), expected: exitRegression},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := exitCode(tc.err); actual != tc.expected {
+				t.Errorf("expected exit code %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitFS is a read-only fs.FS of the files of a git revision,
+// with paths relative to the current directory.
+// Only files can be opened, not directories.
+type gitFS struct {
+	revision string
+}
+
+// compile-time interface checks
+var _ fs.FS = &gitFS{}
+var _ fs.File = &gitFile{}
+var _ fs.FileInfo = &gitFile{}
+
+func newGitFS(revision string) (*gitFS, error) {
+	_, err := git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown git revision %q: %w", revision, err)
+	}
+
+	return &gitFS{revision: revision}, nil
+}
+
+func (g *gitFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	// the revision is valid, so failures mean that there is no such file
+	content, err := git("cat-file", "blob", g.revision+":./"+name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &gitFile{name: name, content: content, Reader: bytes.NewReader(content)}, nil
+}
+
+// gitFile is a file of a gitFS, and its own fs.FileInfo
+type gitFile struct {
+	name    string
+	content []byte
+	*bytes.Reader
+}
+
+func (f *gitFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *gitFile) Close() error               { return nil }
+func (f *gitFile) Name() string               { return f.name[strings.LastIndex(f.name, "/")+1:] }
+func (f *gitFile) Size() int64                { return int64(len(f.content)) }
+func (f *gitFile) Mode() fs.FileMode          { return 0o444 }
+func (f *gitFile) ModTime() time.Time         { return time.Time{} }
+func (f *gitFile) IsDir() bool                { return false }
+func (f *gitFile) Sys() any                   { return nil }
+
+// git runs git with args in the current directory and returns its standard output
+func git(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return nil, fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, message)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// runGraph writes the dependency graph of a package as resolved during its evaluation,
+// in DOT (the default), GraphML or JSON
+func runGraph() error {
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	graphFormat := "dot"
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "format" {
+			graphFormat = f.Value.String()
+		}
+	})
+
+	err = validatePackageFlags()
+	if err == nil {
+		err = validateGraphFormat(graphFormat)
+	}
+	if err != nil {
+		flag.Usage()
+		return &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
+
+	a, err := newApp(aggregdepscore.WithEvaluationTree())
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	evaluation, err := a.evaluate(context.Background(), packageFromFlags())
+	if err != nil {
+		return fmt.Errorf("evaluating score: %w", err)
+	}
+
+	graph := evaluation.Tree.Graph()
+
+	switch graphFormat {
+	case "dot":
+		err = graph.WriteDOT(os.Stdout)
+	case "graphml":
+		err = graph.WriteGraphML(os.Stdout)
+	case "json":
+		err = graph.WriteJSON(os.Stdout)
+	}
+	if err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+
+	return nil
+}
+
+func validateGraphFormat(format string) error {
+	switch format {
+	case "dot", "graphml", "json":
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for graph: %q", format)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// maxContributors is the number of packages in the table of worst contributors of HTML reports
+const maxContributors = 25
+
+//go:embed report.html
+var reportSource string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportSource))
+
+// reportData is the data of the HTML report template
+type reportData struct {
+	Title        string
+	Generated    string
+	Result       result
+	Graph        reportGraph
+	Contributors []reportContributor
+	Parameters   []reportParameter
+}
+
+// reportGraph is the graph of evaluated packages, embedded in the report as JSON;
+// scores are the trustworthiness converted to scores, between 0 and 1, for coloring
+type reportGraph struct {
+	Nodes []*reportNode `json:"nodes"`
+	Edges []reportEdge  `json:"edges"`
+}
+
+type reportNode struct {
+	ID              int      `json:"id"`
+	Label           string   `json:"label"`
+	Depth           int      `json:"depth"`
+	Intrinsic       float64  `json:"intrinsic"`
+	Aggregated      float64  `json:"aggregated"`
+	IntrinsicScore  float64  `json:"intrinsicScore"`
+	AggregatedScore float64  `json:"aggregatedScore"`
+	NbPaths         int      `json:"nbPaths"`
+	Notes           []string `json:"notes"`
+}
+
+type reportEdge struct {
+	Source int  `json:"source"`
+	Target int  `json:"target"`
+	Cycle  bool `json:"cycle"`
+}
+
+type reportContributor struct {
+	Package    string
+	Factor     string
+	Intrinsic  float64
+	Aggregated float64
+	NbPaths    int
+	Depth      int
+}
+
+type reportParameter struct {
+	Name  string
+	Value string
+}
+
+// writeHTML writes a self-contained HTML report of evaluation, which must have a tree
+func writeHTML(w io.Writer, evaluation *aggregdepscore.Evaluation, r result) error {
+	if evaluation.Tree == nil {
+		return fmt.Errorf("the HTML report requires the evaluation tree")
+	}
+
+	graph, nodes := newReportGraph(evaluation.Tree)
+
+	data := reportData{
+		Title:        "Aggregated dependency score of " + describePackage(r.Package),
+		Generated:    time.Now().UTC().Format(time.RFC3339),
+		Result:       r,
+		Graph:        graph,
+		Contributors: worstContributors(evaluation.Tree, nodes),
+		Parameters:   reportParameters(),
+	}
+
+	return reportTemplate.Execute(w, data)
+}
+
+// newReportGraph returns the graph of tree and its nodes by package
+func newReportGraph(tree *aggregdepscore.Node) (reportGraph, map[aggregdepscore.Package]*reportNode) {
+	converter := modelParameters().Converter()
+
+	g := tree.Graph()
+	graph := reportGraph{Nodes: []*reportNode{}, Edges: []reportEdge{}}
+	nodes := make(map[aggregdepscore.Package]*reportNode)
+
+	for i, n := range g.Nodes {
+		node := &reportNode{
+			ID:              i,
+			Label:           describePackage(newPackageOutput(n.Package)),
+			Depth:           n.Depth,
+			Intrinsic:       n.IntrinsicTrustworthiness,
+			Aggregated:      n.AggregatedTrustworthiness,
+			IntrinsicScore:  converter.ScoreFromTrustworthiness(n.IntrinsicTrustworthiness),
+			AggregatedScore: converter.ScoreFromTrustworthiness(n.AggregatedTrustworthiness),
+			NbPaths:         n.NbPaths,
+			Notes:           []string{},
+		}
+		if n.Fallback != nil {
+			node.Notes = append(node.Notes, "intrinsic trustworthiness assumed: "+n.Fallback.Reason)
+		}
+		if n.Truncation != nil {
+			node.Notes = append(node.Notes, "truncated: "+n.Truncation.Limit+" limit")
+		}
+
+		nodes[n.Package] = node
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, edge := range g.Edges {
+		graph.Edges = append(graph.Edges, reportEdge{Source: nodes[edge.From].ID, Target: nodes[edge.To].ID, Cycle: edge.Cycle})
+	}
+
+	return graph, nodes
+}
+
+// worstContributors returns the packages that lower the aggregated trustworthiness of tree the most
+func worstContributors(tree *aggregdepscore.Node, nodes map[aggregdepscore.Package]*reportNode) []reportContributor {
+	type contribution struct {
+		pkg             aggregdepscore.Package
+		logContribution float64
+	}
+
+	var contributions []contribution
+	for p, c := range tree.LogContributions() {
+		if c < 0 {
+			contributions = append(contributions, contribution{pkg: p, logContribution: c})
+		}
+	}
+
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].logContribution != contributions[j].logContribution {
+			return contributions[i].logContribution < contributions[j].logContribution
+		}
+		return nodes[contributions[i].pkg].ID < nodes[contributions[j].pkg].ID
+	})
+
+	if len(contributions) > maxContributors {
+		contributions = contributions[:maxContributors]
+	}
+
+	var result []reportContributor
+	for _, c := range contributions {
+		node := nodes[c.pkg]
+		result = append(result, reportContributor{
+			Package:    node.Label,
+			Factor:     fmt.Sprintf("×%.4g", math.Exp(c.logContribution)),
+			Intrinsic:  node.Intrinsic,
+			Aggregated: node.Aggregated,
+			NbPaths:    node.NbPaths,
+			Depth:      node.Depth,
+		})
+	}
+
+	return result
+}
+
+// reportParameters describes the parameters of the model and the flags that change the evaluation
+func reportParameters() []reportParameter {
+	model := modelParameters()
+
+	parameters := []reportParameter{
+		{"Transitive trustworthiness exponent (e)", formatFloat(model.TransitiveTrustworthinessExponent)},
+		{"Trustworthiness of a score of 0", formatFloat(model.MinTrustworthiness)},
+		{"Score conversion offset (k)", formatFloat(model.TrustworthinessOffset)},
+		{"Repository selection", *repoSelect},
+	}
+
+	optional := func(name string, value string, enabled bool) {
+		if !enabled {
+			value = "none"
+		}
+		parameters = append(parameters, reportParameter{name, value})
+	}
+
+	overridesSource := *overrides
+	if overridesSource == "" && config != nil && len(config.Overrides) > 0 {
+		overridesSource = "configuration file"
+	}
+
+	optional("Trust domains", *trustDomain, *trustDomain != "")
+	optional("Intrinsic trustworthiness fallback", formatFloat(*fallback), *fallback >= 0)
+	optional("Trust overrides", overridesSource, overridesSource != "")
+	optional("Scope",
+		fmt.Sprintf("ecosystems %q, excluded %q", splitList(*scopeEcosystems), splitList(*scopeExclude)),
+		*scopeEcosystems != "" || *scopeExclude != "")
+	optional("Limits",
+		fmt.Sprintf("depth %d, nodes %d, calls %d (0 is unlimited), truncation %s", *maxDepth, *maxNodes, *maxCalls, *truncation),
+		*maxDepth != 0 || *maxNodes != 0 || *maxCalls != 0)
+
+	return parameters
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// result is the outcome of the evaluation of one package, as output by depscore
+type result struct {
+	Package         packageOutput    `json:"package"`
+	Score           float64          `json:"score"`
+	RankingScore    *float64         `json:"ranking_score"`
+	Trustworthiness trustworthiness  `json:"trustworthiness"`
+	NbNodes         int              `json:"nb_nodes"`
+	Fallbacks       []fallbackOutput `json:"fallbacks"`
+	Warnings        []string         `json:"warnings"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	// Dependencies are the direct dependencies of the package
+	Dependencies []dependencyOutput `json:"dependencies"`
+}
+
+type dependencyOutput struct {
+	Package                      packageOutput `json:"package"`
+	Score                        float64       `json:"score"`
+	RankingScore                 *float64      `json:"ranking_score"`
+	AggregatedTrustworthiness    float64       `json:"aggregated_trustworthiness"`
+	LogAggregatedTrustworthiness *float64      `json:"log_aggregated_trustworthiness"`
+	// File and Line tell where the dependency is declared (for projects only)
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+type packageOutput struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+}
+
+type trustworthiness struct {
+	Aggregated    float64  `json:"aggregated"`
+	Intrinsic     float64  `json:"intrinsic"`
+	Transitive    float64  `json:"transitive"`
+	LogAggregated *float64 `json:"log_aggregated"`
+}
+
+type fallbackOutput struct {
+	Package                  packageOutput `json:"package"`
+	IntrinsicTrustworthiness float64       `json:"intrinsic_trustworthiness"`
+	Reason                   string        `json:"reason"`
+}
+
+func newPackageOutput(p aggregdepscore.Package) packageOutput {
+	return packageOutput{Ecosystem: p.Ecosystem, Name: p.Name, Version: p.Version}
+}
+
+func newResult(evaluation *aggregdepscore.Evaluation, duration time.Duration) result {
+	r := result{
+		Package:      newPackageOutput(evaluation.Package),
+		Score:        evaluation.Score,
+		RankingScore: finite(evaluation.RankingScore),
+		Trustworthiness: trustworthiness{
+			Aggregated:    evaluation.Components.Aggregated,
+			Intrinsic:     evaluation.Components.Intrinsic,
+			Transitive:    evaluation.Components.Transitive,
+			LogAggregated: finite(evaluation.Components.LogAggregated),
+		},
+		NbNodes:         evaluation.NbNodes,
+		Fallbacks:       []fallbackOutput{},
+		Warnings:        []string{},
+		DurationSeconds: duration.Seconds(),
+		Dependencies:    []dependencyOutput{},
+	}
+
+	for _, dep := range evaluation.DirectDependencies {
+		r.Dependencies = append(r.Dependencies, dependencyOutput{
+			Package:                      newPackageOutput(dep.Package),
+			Score:                        dep.Score,
+			RankingScore:                 finite(dependencyRankingScore(dep)),
+			AggregatedTrustworthiness:    dep.AggregatedTrustworthiness,
+			LogAggregatedTrustworthiness: finite(dep.LogAggregatedTrustworthiness),
+		})
+	}
+
+	for _, f := range evaluation.Fallbacks {
+		r.Fallbacks = append(r.Fallbacks, fallbackOutput{
+			Package:                  newPackageOutput(f.Package),
+			IntrinsicTrustworthiness: f.IntrinsicTrustworthiness,
+			Reason:                   f.Reason,
+		})
+	}
+
+	if evaluation.Truncated {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("evaluation was truncated %d times, the score is approximate", len(evaluation.Truncations)))
+	}
+
+	if len(evaluation.Fallbacks) > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("intrinsic trustworthiness was assumed for %d packages", len(evaluation.Fallbacks)))
+	}
+
+	if len(evaluation.BrokenCycles) > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("%d dependency cycles were ignored", len(evaluation.BrokenCycles)))
+	}
+
+	return r
+}
+
+// writeResults writes results in one of the output formats: text, json, csv or markdown
+// (see writeHTML for html)
+func writeResults(w io.Writer, format string, results []result) error {
+	switch format {
+	case "text":
+		return writeText(w, results)
+	case "json":
+		return writeJSON(w, results)
+	case "csv":
+		return writeCSV(w, results)
+	case "markdown":
+		return writeMarkdown(w, results)
+	default:
+		return fmt.Errorf("unknown output format: %q", format)
+	}
+}
+
+func validateFormat(format string) error {
+	switch format {
+	case "text", "json", "csv", "markdown", "html":
+		return nil
+	default:
+		return fmt.Errorf("unknown output format: %q", format)
+	}
+}
+
+// writeText writes the bare score for a single result (as depscore always did),
+// and one line per package otherwise
+func writeText(w io.Writer, results []result) error {
+	if len(results) == 1 {
+		_, err := fmt.Fprintln(w, results[0].Score)
+		return err
+	}
+
+	for _, r := range results {
+		_, err := fmt.Fprintf(w, "%s %s %s %g\n", r.Package.Ecosystem, r.Package.Name, r.Package.Version, r.Score)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSON writes a single object for a single result, and an array otherwise
+func writeJSON(w io.Writer, results []result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if len(results) == 1 {
+		return encoder.Encode(results[0])
+	}
+
+	return encoder.Encode(results)
+}
+
+var csvHeader = []string{
+	"ecosystem", "name", "version",
+	"score", "ranking_score", "aggregated_trustworthiness",
+	"nb_nodes", "nb_fallbacks", "warnings", "duration_seconds",
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		err := writer.Write([]string{
+			r.Package.Ecosystem, r.Package.Name, r.Package.Version,
+			formatFloat(r.Score), formatOptionalFloat(r.RankingScore), formatFloat(r.Trustworthiness.Aggregated),
+			strconv.Itoa(r.NbNodes), strconv.Itoa(len(r.Fallbacks)), strings.Join(r.Warnings, "; "), formatFloat(r.DurationSeconds),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdown(w io.Writer, results []result) error {
+	var b strings.Builder
+
+	b.WriteString("| Ecosystem | Package | Version | Score | Aggregated trustworthiness | Nodes | Warnings |\n")
+	b.WriteString("|---|---|---|---:|---:|---:|---|\n")
+
+	for _, r := range results {
+		fmt.Fprintf(&b, "| %s | %s | %s | %.3f | %.3g | %d | %s |\n",
+			escapeMarkdown(r.Package.Ecosystem),
+			escapeMarkdown(r.Package.Name),
+			escapeMarkdown(r.Package.Version),
+			r.Score,
+			r.Trustworthiness.Aggregated,
+			r.NbNodes,
+			escapeMarkdown(strings.Join(r.Warnings, "; ")),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+
+	return formatFloat(*f)
+}
+
+// dependencyRankingScore is the ranking score of a direct dependency,
+// computed like aggregdepscore.Evaluation.RankingScore
+func dependencyRankingScore(dep aggregdepscore.DirectDependency) float64 {
+	if dep.Score > 0 {
+		return dep.Score
+	}
+
+	return 1 - dep.LogAggregatedTrustworthiness/math.Log(*minTrust)
+}
+
+// finite returns nil for infinite values (which happen when a trustworthiness is zero)
+// because they cannot be represented in JSON
+func finite(f float64) *float64 {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+
+	return &f
+}
+
+var markdownReplacer = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// progressLine is an observer rendering a live progress line,
+// overwritten in place using a carriage return
+type progressLine struct {
+	aggregdepscore.NopObserver
+
+	mutex      sync.Mutex
+	output     io.Writer
+	nbStarted  int
+	nbFinished int
+	nbCycles   int
+	maxDepth   int
+	current    string
+	lastRender time.Time
+}
+
+// renderInterval limits how often the line is redrawn
+const renderInterval = 100 * time.Millisecond
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (p *progressLine) NodeStarted(ctx context.Context, event aggregdepscore.NodeStartedEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.nbStarted++
+	p.maxDepth = max(p.maxDepth, event.Depth)
+	p.current = event.Package.Name
+	p.render(false)
+}
+
+func (p *progressLine) NodeFinished(ctx context.Context, event aggregdepscore.NodeFinishedEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.nbFinished++
+	p.render(false)
+}
+
+func (p *progressLine) CycleSkipped(ctx context.Context, cycle aggregdepscore.Cycle) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.nbCycles++
+}
+
+// render must be called with the mutex held
+func (p *progressLine) render(force bool) {
+	if !force && time.Since(p.lastRender) < renderInterval {
+		return
+	}
+	p.lastRender = time.Now()
+
+	// "\033[K" clears the rest of the line
+	fmt.Fprintf(p.output, "\r\033[Kevaluated %d/%d packages, max depth %d, %d cycles skipped, current: %s",
+		p.nbFinished, p.nbStarted, p.maxDepth, p.nbCycles, p.current)
+}
+
+// clear erases the progress line so that it does not mix with the result
+func (p *progressLine) clear() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	fmt.Fprint(p.output, "\r\033[K")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/lockfile"
+)
+
+// runProject evaluates a project from the manifests and lockfiles in a directory
+func runProject() error {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s project [flags] [path]\n\nSupported files: %s\n\n", os.Args[0], strings.Join(lockfile.Filenames(), ", "))
+		flag.PrintDefaults()
+	}
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	err = validateProjectFormat(*format)
+	if err != nil {
+		flag.Usage()
+		return &flagError{err: fmt.Errorf("validating flags: %w", err)}
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	a, err := newApp(formatOptions(*format)...)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	start := time.Now()
+	evaluation, files, err := a.evaluateProject(context.Background(), dir)
+	if err != nil {
+		return err
+	}
+
+	r := newProjectResult(evaluation, files, time.Since(start))
+
+	projectDir := dir
+	if lockfile.Supported(dir) {
+		projectDir = filepath.Dir(dir)
+	}
+
+	if *format == "html" {
+		err = writeHTML(os.Stdout, evaluation, r)
+	} else {
+		err = writeProjectResult(os.Stdout, *format, r, projectDir)
+	}
+	if err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+
+	return checkGates(os.Stderr, []result{r})
+}
+
+// evaluateProject evaluates the project in dir, which can be a directory or a supported file
+func (a *app) evaluateProject(ctx context.Context, dir string) (*aggregdepscore.Evaluation, []*lockfile.File, error) {
+	absolute, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files, err := readProject(os.DirFS(filepath.Dir(absolute)), filepath.Base(absolute))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading dependencies of %s: %w", dir, err)
+	}
+
+	evaluation, err := a.evaluateFiles(ctx, projectName(absolute), files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return evaluation, files, nil
+}
+
+// readProject reads the files of the project at name in fsys,
+// which can be a directory or a supported file
+func readProject(fsys fs.FS, name string) ([]*lockfile.File, error) {
+	if lockfile.Supported(name) {
+		dir, err := fs.Sub(fsys, path.Dir(name))
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := lockfile.Parse(dir, path.Base(name))
+		if err != nil {
+			return nil, err
+		}
+
+		return []*lockfile.File{f}, nil
+	}
+
+	dir, err := fs.Sub(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockfile.Detect(dir)
+}
+
+// projectName is the name of the directory of the project at the absolute path p
+func projectName(p string) string {
+	if lockfile.Supported(p) {
+		p = filepath.Dir(p)
+	}
+
+	return filepath.Base(p)
+}
+
+// evaluateFiles evaluates the project named name with the dependencies declared in files that are in scope
+func (a *app) evaluateFiles(ctx context.Context, name string, files []*lockfile.File) (*aggregdepscore.Evaluation, error) {
+	for _, f := range files {
+		a.logger.Info("read dependencies", slog.String("file", f.Path), slog.Int("nb_dependencies", len(f.Dependencies)))
+	}
+
+	evaluation, err := a.evaluator.EvaluateProject(ctx, name, a.scope.filter(lockfile.Packages(files)))
+	if a.progress != nil {
+		a.progress.clear()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("evaluating project: %w", err)
+	}
+
+	return evaluation, nil
+}
+
+func newProjectResult(evaluation *aggregdepscore.Evaluation, files []*lockfile.File, duration time.Duration) result {
+	r := newResult(evaluation, duration)
+
+	// where each dependency is declared
+	type declaration struct {
+		file string
+		line int
+	}
+
+	declared := make(map[aggregdepscore.Package]declaration)
+	for _, f := range files {
+		for _, dep := range f.Dependencies {
+			if _, ok := declared[dep.Package]; !ok {
+				declared[dep.Package] = declaration{file: f.Path, line: dep.Line}
+			}
+		}
+
+		for _, warning := range f.Warnings {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: %s", f.Path, warning))
+		}
+	}
+
+	for i, dep := range evaluation.DirectDependencies {
+		d := declared[dep.Package]
+		r.Dependencies[i].File = d.file
+		r.Dependencies[i].Line = d.line
+	}
+
+	return r
+}
+
+// validateProjectFormat validates the output formats of projects,
+// which can also be written as SARIF
+func validateProjectFormat(format string) error {
+	if format == "sarif" {
+		return nil
+	}
+
+	return validateFormat(format)
+}
+
+// writeProjectResult writes the result of a project and of its direct dependencies;
+// dir is the directory of the project
+func writeProjectResult(w io.Writer, format string, r result, dir string) error {
+	switch format {
+	case "sarif":
+		return writeSARIF(w, r, dir)
+	case "json":
+		return writeJSON(w, []result{r})
+	case "text":
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %g\n", r.Package.Name, r.Score)
+		for _, dep := range r.Dependencies {
+			fmt.Fprintf(&b, "  %s %s %s %g\n", dep.Package.Ecosystem, dep.Package.Name, dep.Package.Version, dep.Score)
+		}
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&b, "warning: %s\n", warning)
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	case "csv":
+		writer := csv.NewWriter(w)
+		rows := [][]string{
+			{"kind", "ecosystem", "name", "version", "score", "aggregated_trustworthiness", "file", "line"},
+			{"project", "", r.Package.Name, "", formatFloat(r.Score), formatFloat(r.Trustworthiness.Aggregated), "", ""},
+		}
+		for _, dep := range r.Dependencies {
+			rows = append(rows, []string{
+				"dependency", dep.Package.Ecosystem, dep.Package.Name, dep.Package.Version,
+				formatFloat(dep.Score), formatFloat(dep.AggregatedTrustworthiness), dep.File, strconv.Itoa(dep.Line),
+			})
+		}
+		return writer.WriteAll(rows)
+	case "markdown":
+		var b strings.Builder
+		fmt.Fprintf(&b, "**%s**: score %.3f, aggregated trustworthiness %.3g (%d nodes)\n\n", escapeMarkdown(r.Package.Name), r.Score, r.Trustworthiness.Aggregated, r.NbNodes)
+		b.WriteString("| Ecosystem | Dependency | Version | Score | Aggregated trustworthiness | Declared in |\n")
+		b.WriteString("|---|---|---|---:|---:|---|\n")
+		for _, dep := range r.Dependencies {
+			fmt.Fprintf(&b, "| %s | %s | %s | %.3f | %.3g | %s:%d |\n",
+				escapeMarkdown(dep.Package.Ecosystem),
+				escapeMarkdown(dep.Package.Name),
+				escapeMarkdown(dep.Package.Version),
+				dep.Score,
+				dep.AggregatedTrustworthiness,
+				escapeMarkdown(dep.File),
+				dep.Line,
+			)
+		}
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&b, "\n> warning: %s\n", escapeMarkdown(warning))
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unknown output format: %q", format)
+	}
+}
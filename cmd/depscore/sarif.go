@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var lowTrust = flag.Float64("low-trust", 0.5, "Ranking score below which a direct dependency is reported as low-trust with -format=sarif")
+
+// SARIF 2.1.0 log, with only the properties depscore uses
+// (see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF rules, in the order of sarifRules
+const (
+	ruleLowTrustDependency = iota
+	ruleBelowMinimumScore
+	ruleAssumedTrustworthiness
+)
+
+var sarifRules = []sarifRule{
+	ruleLowTrustDependency: {
+		ID:               "DEPSCORE001",
+		Name:             "LowTrustDependency",
+		ShortDescription: sarifMessage{Text: "Low-trust dependency"},
+		FullDescription: sarifMessage{Text: "The aggregated dependency score of this direct dependency, " +
+			"which includes its transitive dependencies, is low."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	ruleBelowMinimumScore: {
+		ID:                   "DEPSCORE002",
+		Name:                 "BelowMinimumScore",
+		ShortDescription:     sarifMessage{Text: "Project score below the minimum"},
+		FullDescription:      sarifMessage{Text: "The aggregated dependency score of the project is below the minimum score set by policy."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+	},
+	ruleAssumedTrustworthiness: {
+		ID:               "DEPSCORE003",
+		Name:             "AssumedTrustworthiness",
+		ShortDescription: sarifMessage{Text: "Dependency trustworthiness assumed"},
+		FullDescription: sarifMessage{Text: "The intrinsic trustworthiness of this direct dependency could not be evaluated " +
+			"and was assumed, so its score may be inaccurate."},
+		DefaultConfiguration: sarifConfiguration{Level: "note"},
+	},
+}
+
+// writeSARIF writes the findings of the project result r as a SARIF log:
+// direct dependencies with a ranking score below -low-trust (errors when below -min-score),
+// a project ranking score below -min-score, and direct dependencies whose intrinsic trustworthiness was assumed.
+// dir is the directory of the project, which the paths of the files of r are relative to.
+func writeSARIF(w io.Writer, r result, dir string) error {
+	results := []sarifResult{}
+
+	newResult := func(rule int, level string, message string, dep *dependencyOutput) sarifResult {
+		s := sarifResult{
+			RuleID:    sarifRules[rule].ID,
+			RuleIndex: rule,
+			Level:     level,
+			Message:   sarifMessage{Text: message},
+		}
+
+		if dep != nil {
+			s.PartialFingerprints = map[string]string{"dependency/v1": packageKey(dep.Package)}
+			s.Properties = map[string]any{
+				"ecosystem":                 dep.Package.Ecosystem,
+				"name":                      dep.Package.Name,
+				"version":                   dep.Package.Version,
+				"score":                     dep.Score,
+				"rankingScore":              ranking(dep.Score, dep.RankingScore),
+				"aggregatedTrustworthiness": dep.AggregatedTrustworthiness,
+			}
+
+			if dep.File != "" {
+				location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifactLocation(dir, dep.File)}}
+				if dep.Line > 0 {
+					location.PhysicalLocation.Region = &sarifRegion{StartLine: dep.Line}
+				}
+				s.Locations = []sarifLocation{location}
+			}
+		}
+
+		return s
+	}
+
+	// like gates, thresholds are compared with ranking scores,
+	// which still tell apart packages whose score is clamped at zero
+	if projectRanking := ranking(r.Score, r.RankingScore); *minScore >= 0 && projectRanking < *minScore {
+		s := newResult(ruleBelowMinimumScore, "error",
+			fmt.Sprintf("The ranking score of %s is %.3f, below the minimum of %.3f.", r.Package.Name, projectRanking, *minScore), nil)
+
+		// the project is declared by all its files
+		declared := make(map[string]bool)
+		for _, dep := range r.Dependencies {
+			if dep.File != "" && !declared[dep.File] {
+				declared[dep.File] = true
+				s.Locations = append(s.Locations, sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifactLocation(dir, dep.File)}})
+			}
+		}
+
+		results = append(results, s)
+	}
+
+	assumed := make(map[packageOutput]fallbackOutput)
+	for _, f := range r.Fallbacks {
+		assumed[f.Package] = f
+	}
+
+	for i := range r.Dependencies {
+		dep := &r.Dependencies[i]
+
+		depRanking := ranking(dep.Score, dep.RankingScore)
+		belowMinimum := *minScore >= 0 && depRanking < *minScore
+		if depRanking < *lowTrust || belowMinimum {
+			level := "warning"
+			if belowMinimum {
+				level = "error"
+			}
+
+			results = append(results, newResult(ruleLowTrustDependency, level,
+				fmt.Sprintf("%s has a ranking score of %.3f (score %.3f, aggregated trustworthiness %.3g), including its transitive dependencies.",
+					describePackage(dep.Package), depRanking, dep.Score, dep.AggregatedTrustworthiness), dep))
+		}
+
+		if f, ok := assumed[dep.Package]; ok {
+			results = append(results, newResult(ruleAssumedTrustworthiness, "note",
+				fmt.Sprintf("The intrinsic trustworthiness of %s was assumed to be %.3g: %s.",
+					describePackage(dep.Package), f.IntrinsicTrustworthiness, f.Reason), dep))
+		}
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "depscore",
+				InformationURI: "https://github.com/DataDog/aggregated-dependency-score",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// artifactLocation is the location of the file at the slash-separated path name in dir,
+// relative to the current directory (the root of the sources when run in CI) if dir is in it
+func artifactLocation(dir string, name string) sarifArtifactLocation {
+	absolute, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return sarifArtifactLocation{URI: name}
+	}
+
+	wd, err := os.Getwd()
+	if err == nil {
+		relative, err := filepath.Rel(wd, absolute)
+		if err == nil && fs.ValidPath(filepath.ToSlash(relative)) {
+			return sarifArtifactLocation{URI: filepath.ToSlash(relative), URIBaseID: "%SRCROOT%"}
+		}
+	}
+
+	uri := filepath.ToSlash(absolute)
+	if !strings.HasPrefix(uri, "/") {
+		// Windows drive letter
+		uri = "/" + uri
+	}
+
+	return sarifArtifactLocation{URI: "file://" + uri}
+}
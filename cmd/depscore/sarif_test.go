@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSARIF(t *testing.T) {
+	// the score of event-stream is clamped at zero but not its ranking score
+	eventStreamRanking := -0.5
+
+	r := result{
+		Package: packageOutput{Name: "project"},
+		Score:   0.15,
+		Dependencies: []dependencyOutput{
+			{Package: packageOutput{Ecosystem: "npm", Name: "express", Version: "4.18.2"}, Score: 0.9, File: "package-lock.json", Line: 12},
+			{Package: packageOutput{Ecosystem: "npm", Name: "left-pad", Version: "1.3.0"}, Score: 0.4, File: "package-lock.json", Line: 30},
+			{Package: packageOutput{Ecosystem: "pypi", Name: "requests", Version: "2.28.1"}, Score: 0.1, File: "api/requirements.txt", Line: 3},
+			{Package: packageOutput{Ecosystem: "npm", Name: "event-stream", Version: "3.3.6"}, Score: 0, RankingScore: &eventStreamRanking, File: "package-lock.json", Line: 45},
+		},
+		Fallbacks: []fallbackOutput{
+			{Package: packageOutput{Ecosystem: "npm", Name: "express", Version: "4.18.2"}, IntrinsicTrustworthiness: 0.5, Reason: "no source repository"},
+		},
+	}
+
+	type finding struct {
+		ruleID    string
+		ruleIndex int
+		level     string
+		uri       string
+		startLine int
+		// message is checked if not empty
+		message string
+	}
+
+	testCases := []struct {
+		name     string
+		minScore float64
+		lowTrust float64
+		expected []finding
+	}{
+		{
+			name:     "low-trust dependencies",
+			minScore: -1,
+			lowTrust: 0.5,
+			expected: []finding{
+				{ruleID: "DEPSCORE003", ruleIndex: ruleAssumedTrustworthiness, level: "note", uri: "project/package-lock.json", startLine: 12},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "warning", uri: "project/package-lock.json", startLine: 30},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "warning", uri: "project/api/requirements.txt", startLine: 3},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "warning", uri: "project/package-lock.json", startLine: 45,
+					message: "npm event-stream@3.3.6 has a ranking score of -0.500 (score 0.000"},
+			},
+		},
+		{
+			name:     "below the minimum score",
+			minScore: 0.2,
+			lowTrust: 0.5,
+			expected: []finding{
+				{ruleID: "DEPSCORE002", ruleIndex: ruleBelowMinimumScore, level: "error", uri: "project/package-lock.json"},
+				{ruleID: "DEPSCORE003", ruleIndex: ruleAssumedTrustworthiness, level: "note", uri: "project/package-lock.json", startLine: 12},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "warning", uri: "project/package-lock.json", startLine: 30},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "error", uri: "project/api/requirements.txt", startLine: 3},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "error", uri: "project/package-lock.json", startLine: 45},
+			},
+		},
+		{
+			name:     "below the minimum score but not low-trust",
+			minScore: 0.5,
+			lowTrust: 0,
+			expected: []finding{
+				{ruleID: "DEPSCORE002", ruleIndex: ruleBelowMinimumScore, level: "error", uri: "project/package-lock.json"},
+				{ruleID: "DEPSCORE003", ruleIndex: ruleAssumedTrustworthiness, level: "note", uri: "project/package-lock.json", startLine: 12},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "error", uri: "project/package-lock.json", startLine: 30},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "error", uri: "project/api/requirements.txt", startLine: 3},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "error", uri: "project/package-lock.json", startLine: 45},
+			},
+		},
+		{
+			name:     "ranking score below zero",
+			minScore: -1,
+			lowTrust: 0,
+			expected: []finding{
+				{ruleID: "DEPSCORE003", ruleIndex: ruleAssumedTrustworthiness, level: "note", uri: "project/package-lock.json", startLine: 12},
+				{ruleID: "DEPSCORE001", ruleIndex: ruleLowTrustDependency, level: "warning", uri: "project/package-lock.json", startLine: 45},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			previousMinScore, previousLowTrust := *minScore, *lowTrust
+			*minScore, *lowTrust = tc.minScore, tc.lowTrust
+			defer func() {
+				*minScore, *lowTrust = previousMinScore, previousLowTrust
+			}()
+
+			var buf bytes.Buffer
+			err := writeSARIF(&buf, r, "project")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var log sarifLog
+			err = json.Unmarshal(buf.Bytes(), &log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(log.Runs) != 1 {
+				t.Fatalf("expected 1 run, got %d", len(log.Runs))
+			}
+
+			run := log.Runs[0]
+			if len(run.Results) != len(tc.expected) {
+				t.Fatalf("expected %d results, got %d: %s", len(tc.expected), len(run.Results), buf.String())
+			}
+
+			for i, expected := range tc.expected {
+				actual := run.Results[i]
+
+				if actual.RuleID != expected.ruleID || actual.RuleIndex != expected.ruleIndex || actual.Level != expected.level {
+					t.Errorf("result %d: expected rule %s (index %d) at level %s, got rule %s (index %d) at level %s",
+						i, expected.ruleID, expected.ruleIndex, expected.level, actual.RuleID, actual.RuleIndex, actual.Level)
+				}
+
+				if expected.message != "" && !strings.Contains(actual.Message.Text, expected.message) {
+					t.Errorf("result %d: expected a message containing %q, got %q", i, expected.message, actual.Message.Text)
+				}
+
+				if run.Tool.Driver.Rules[actual.RuleIndex].ID != actual.RuleID {
+					t.Errorf("result %d: rule index %d is not rule %s", i, actual.RuleIndex, actual.RuleID)
+				}
+
+				if len(actual.Locations) == 0 {
+					t.Errorf("result %d: expected a location", i)
+					continue
+				}
+
+				location := actual.Locations[0].PhysicalLocation
+				if location.ArtifactLocation.URI != expected.uri || location.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+					t.Errorf("result %d: expected location %s relative to %%SRCROOT%%, got %+v", i, expected.uri, location.ArtifactLocation)
+				}
+
+				startLine := 0
+				if location.Region != nil {
+					startLine = location.Region.StartLine
+				}
+				if startLine != expected.startLine {
+					t.Errorf("result %d: expected start line %d, got %d", i, expected.startLine, startLine)
+				}
+			}
+		})
+	}
+}
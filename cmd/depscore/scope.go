@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+var (
+	scopeEcosystems = flag.String("scope-ecosystems", "", "Comma-separated ecosystems of the direct dependencies of projects to evaluate (all if empty)")
+	scopeExclude    = flag.String("scope-exclude", "", "Comma-separated globs of names of direct dependencies of projects not to evaluate, e.g. @acme/*")
+)
+
+// scope selects the direct dependencies of projects that are evaluated
+type scope struct {
+	// ecosystems is empty if all ecosystems are in scope
+	ecosystems map[string]bool
+	// exclude are globs with the syntax of path.Match
+	exclude []string
+}
+
+// newScope creates a scope from comma-separated ecosystems and name globs
+func newScope(ecosystems string, exclude string) (*scope, error) {
+	s := &scope{ecosystems: make(map[string]bool)}
+
+	for _, e := range splitList(ecosystems) {
+		s.ecosystems[e] = true
+	}
+
+	for _, glob := range splitList(exclude) {
+		_, err := path.Match(glob, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope exclusion %q: %w", glob, err)
+		}
+
+		s.exclude = append(s.exclude, glob)
+	}
+
+	return s, nil
+}
+
+// filter returns the packages that are in scope, all of them if s is nil
+func (s *scope) filter(packages []aggregdepscore.Package) []aggregdepscore.Package {
+	if s == nil {
+		return packages
+	}
+
+	var result []aggregdepscore.Package
+	for _, p := range packages {
+		if s.includes(p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func (s *scope) includes(p aggregdepscore.Package) bool {
+	if len(s.ecosystems) > 0 && !s.ecosystems[p.Ecosystem] {
+		return false
+	}
+
+	for _, glob := range s.exclude {
+		// globs were validated by newScope
+		if matched, _ := path.Match(glob, p.Name); matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitList splits a comma-separated list, ignoring empty elements
+func splitList(list string) []string {
+	var result []string
+	for _, element := range strings.Split(list, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			result = append(result, element)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/depscorepb"
+	"github.com/DataDog/aggregated-dependency-score/depscoreserver"
+	"github.com/DataDog/aggregated-dependency-score/lockfile"
+)
+
+// runServe serves an HTTP JSON API to evaluate packages and projects,
+// and optionally the DependencyScoreService gRPC API,
+// with a cache shared by all requests
+func runServe() error {
+	listen := flag.String("listen", ":8080", "Address to listen on")
+	grpcListen := flag.String("grpc-listen", "", "Address to serve the gRPC API on (disabled if empty)")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "How long intrinsic trustworthiness and dependencies are cached")
+	requestTimeout := flag.Duration("request-timeout", 2*time.Minute, "Maximum duration of the evaluations of a request")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 30*time.Second, "How long in-flight requests are waited for on shutdown")
+	maxBatch := flag.Int("max-batch", 1000, "Maximum number of packages in a batch request")
+	maxUpload := flag.Int64("max-upload", 10<<20, "Maximum size in bytes of the files uploaded to evaluate a project")
+	err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	// there is no terminal to display progress on
+	*progress = false
+
+	opts := []aggregdepscore.EvaluatorOption{aggregdepscore.WithCache(*cacheTTL), aggregdepscore.WithConcurrency(8)}
+	if *grpcListen != "" {
+		// for the Explain RPC
+		opts = append(opts, aggregdepscore.WithEvaluationTree())
+	}
+
+	a, err := newApp(opts...)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	s := &server{
+		evaluator:      a.evaluator,
+		logger:         a.logger,
+		scope:          a.scope,
+		requestTimeout: *requestTimeout,
+		maxBatch:       *maxBatch,
+		maxUpload:      *maxUpload,
+	}
+
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	var grpcServer *grpc.Server
+	if *grpcListen != "" {
+		listener, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			return fmt.Errorf("listening for gRPC: %w", err)
+		}
+
+		grpcServer = grpc.NewServer()
+		depscorepb.RegisterDependencyScoreServiceServer(grpcServer, depscoreserver.New(a.evaluator, depscoreserver.WithLogger(a.logger)))
+		go func() {
+			errs <- grpcServer.Serve(listener)
+		}()
+
+		a.logger.Info("serving gRPC", slog.String("address", *grpcListen))
+	}
+
+	s.ready.Store(true)
+	a.logger.Info("serving", slog.String("address", *listen))
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("serving: %w", err)
+	case <-ctx.Done():
+	}
+
+	// stop receiving traffic from load balancers, then wait for in-flight requests
+	s.ready.Store(false)
+	a.logger.Info("shutting down", slog.Duration("timeout", *shutdownTimeout))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	if grpcServer != nil {
+		go func() {
+			<-shutdownCtx.Done()
+			grpcServer.Stop()
+		}()
+		// stops immediately once shutdownCtx is done
+		defer grpcServer.GracefulStop()
+	}
+
+	err = httpServer.Shutdown(shutdownCtx)
+	if err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+
+	return nil
+}
+
+// server is the HTTP API of depscore serve
+type server struct {
+	evaluator *aggregdepscore.Evaluator
+	logger    *slog.Logger
+	// scope selects the direct dependencies of uploaded projects (all if nil)
+	scope          *scope
+	requestTimeout time.Duration
+	maxBatch       int
+	maxUpload      int64
+	// ready is false until the server listens and once it shuts down
+	ready atomic.Bool
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/score", s.handleScore)
+	mux.HandleFunc("POST /v1/score/batch", s.handleBatch)
+	mux.HandleFunc("POST /v1/project", s.handleProject)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+
+	return mux
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type batchRequest struct {
+	Purls []string `json:"purls"`
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// batchResult has either a result or an error
+type batchResult struct {
+	Purl   string  `json:"purl"`
+	Result *result `json:"result,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+// handleScore evaluates the package of the purl query parameter
+func (s *server) handleScore(w http.ResponseWriter, r *http.Request) {
+	p, err := aggregdepscore.ParsePurl(r.URL.Query().Get("purl"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	evaluation, err := s.evaluator.Evaluate(ctx, p)
+	if err != nil {
+		s.writeError(w, evaluationErrorStatus(err), fmt.Errorf("evaluating %s: %w", describePackage(newPackageOutput(p)), err))
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, newResult(evaluation, time.Since(start)))
+}
+
+// handleBatch evaluates the packages of a JSON body like {"purls": ["pkg:npm/express@4.18.2"]};
+// results are in the order of purls, with an error for the ones that could not be evaluated
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var request batchRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxUpload)).Decode(&request)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+
+	if len(request.Purls) > s.maxBatch {
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("too many packages: %d, the maximum is %d", len(request.Purls), s.maxBatch))
+		return
+	}
+
+	response := batchResponse{Results: make([]batchResult, len(request.Purls))}
+
+	var packages []aggregdepscore.Package
+	// indexes of packages in response.Results
+	var indexes []int
+	for i, purl := range request.Purls {
+		response.Results[i].Purl = purl
+
+		p, err := aggregdepscore.ParsePurl(purl)
+		if err != nil {
+			response.Results[i].Error = err.Error()
+			continue
+		}
+
+		packages = append(packages, p)
+		indexes = append(indexes, i)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	for _, scoreResult := range s.evaluator.EvaluateScores(ctx, packages) {
+		i := indexes[scoreResult.Index]
+		if scoreResult.Err != nil {
+			response.Results[i].Error = scoreResult.Err.Error()
+			continue
+		}
+
+		res := newResult(scoreResult.Evaluation, time.Since(start))
+		response.Results[i].Result = &res
+	}
+
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// handleProject evaluates a project from the manifests and lockfiles uploaded as multipart/form-data
+// (see lockfile.Filenames for the supported file names);
+// the name query parameter is the name of the project
+func (s *server) handleProject(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUpload)
+
+	err := r.ParseMultipartForm(s.maxUpload)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("reading uploaded files: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	dir, err := os.MkdirTemp("", "depscore-project-")
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	for _, headers := range r.MultipartForm.File {
+		for _, header := range headers {
+			err := saveUpload(header, dir)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, fmt.Errorf("reading %s: %w", header.Filename, err))
+				return
+			}
+		}
+	}
+
+	files, err := readProject(os.DirFS(dir), ".")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("reading dependencies: %w", err))
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "project"
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	evaluation, err := s.evaluator.EvaluateProject(ctx, name, s.scope.filter(lockfile.Packages(files)))
+	if err != nil {
+		s.writeError(w, evaluationErrorStatus(err), fmt.Errorf("evaluating project: %w", err))
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, newProjectResult(evaluation, files, time.Since(start)))
+}
+
+// saveUpload writes an uploaded file in dir, under its base name,
+// failing if a file of the same name was already uploaded
+func saveUpload(header *multipart.FileHeader, dir string) error {
+	name := filepath.Base(filepath.Clean("/" + header.Filename))
+	if name == "/" || name == "." {
+		return fmt.Errorf("invalid file name")
+	}
+
+	in, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("several files named %s", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, statusResponse{Status: "ok"})
+}
+
+func (s *server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		s.writeJSON(w, http.StatusServiceUnavailable, statusResponse{Status: "not ready"})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, statusResponse{Status: "ready"})
+}
+
+// evaluationErrorStatus is the HTTP status of an evaluation error
+func evaluationErrorStatus(err error) int {
+	var limitExceeded *aggregdepscore.ErrLimitExceeded
+
+	switch {
+	case errors.As(err, &limitExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case status.Code(err) == codes.NotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func (s *server) writeError(w http.ResponseWriter, code int, err error) {
+	if code >= http.StatusInternalServerError {
+		s.logger.Warn("request failed", slog.Int("status", code), slog.String("error", err.Error()))
+	}
+
+	s.writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		s.logger.Warn("writing response", slog.String("error", err.Error()))
+	}
+}
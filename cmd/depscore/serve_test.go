@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "deps.dev/api/v3"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+const testPackageLock = `{
+  "name": "app",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app", "dependencies": {"express": "^4.18.2"}},
+    "node_modules/express": {"version": "4.18.2"},
+    "node_modules/debug": {"version": "2.6.9"}
+  }
+}
+`
+
+func testServer(t *testing.T) (*server, *depsdotdevfake.Fake) {
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/expressjs/express", 8)
+	fake.AddProject("github.com/debug-js/debug", 6)
+	fake.AddVersion(api.System_NPM, "debug", "2.6.9", []string{"github.com/debug-js/debug"})
+	fake.AddVersion(api.System_NPM, "express", "4.18.2", []string{"github.com/expressjs/express"},
+		&api.VersionKey{System: api.System_NPM, Name: "debug", Version: "2.6.9"})
+
+	client, err := aggregdepscore.NewDepsDotDevClient(aggregdepscore.WithInsightsClient(fake))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluator, err := aggregdepscore.NewEvaluator(client, client, aggregdepscore.WithCache(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &server{
+		evaluator:      evaluator,
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		requestTimeout: time.Minute,
+		maxBatch:       2,
+		maxUpload:      1 << 20,
+	}
+	s.ready.Store(true)
+
+	return s, fake
+}
+
+func projectUpload(t *testing.T, files map[string]string) (io.Reader, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := w.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, _ = io.WriteString(part, content)
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return &body, w.FormDataContentType()
+}
+
+func TestServe(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		contentType    string
+		body           func(t *testing.T) (io.Reader, string)
+		expectedStatus int
+		// expected is a substring of the response body
+		expected string
+	}{
+		{name: "score", method: http.MethodGet, target: "/v1/score?purl=pkg:npm/express@4.18.2", expectedStatus: http.StatusOK, expected: `"name":"express"`},
+		{name: "invalid purl", method: http.MethodGet, target: "/v1/score?purl=npm/express", expectedStatus: http.StatusBadRequest, expected: `"error":"invalid purl`},
+		{name: "unknown package", method: http.MethodGet, target: "/v1/score?purl=pkg:npm/unknown@1.0.0", expectedStatus: http.StatusNotFound, expected: `"error":`},
+		{
+			name:   "batch",
+			method: http.MethodPost,
+			target: "/v1/score/batch",
+			body: func(t *testing.T) (io.Reader, string) {
+				return strings.NewReader(`{"purls": ["pkg:npm/debug@2.6.9", "pkg:gem/rails@7.1.0"]}`), "application/json"
+			},
+			expectedStatus: http.StatusOK,
+			expected:       `"error":"unsupported purl type \"gem\""`,
+		},
+		{
+			name:   "batch too large",
+			method: http.MethodPost,
+			target: "/v1/score/batch",
+			body: func(t *testing.T) (io.Reader, string) {
+				return strings.NewReader(`{"purls": ["pkg:npm/a@1", "pkg:npm/b@1", "pkg:npm/c@1"]}`), "application/json"
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expected:       "too many packages",
+		},
+		{
+			name:   "project",
+			method: http.MethodPost,
+			target: "/v1/project?name=app",
+			body: func(t *testing.T) (io.Reader, string) {
+				return projectUpload(t, map[string]string{"package-lock.json": testPackageLock})
+			},
+			expectedStatus: http.StatusOK,
+			expected:       `"name":"app"`,
+		},
+		{
+			name:   "project without supported files",
+			method: http.MethodPost,
+			target: "/v1/project",
+			body: func(t *testing.T) (io.Reader, string) {
+				return projectUpload(t, map[string]string{"notes.txt": "hello"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expected:       `"error":"reading dependencies`,
+		},
+		{
+			name:   "project with files of the same name",
+			method: http.MethodPost,
+			target: "/v1/project",
+			body: func(t *testing.T) (io.Reader, string) {
+				return projectUpload(t, map[string]string{"a/package-lock.json": testPackageLock, "b/package-lock.json": testPackageLock})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expected:       "several files named package-lock.json",
+		},
+		{name: "health", method: http.MethodGet, target: "/healthz", expectedStatus: http.StatusOK, expected: `"status":"ok"`},
+		{name: "ready", method: http.MethodGet, target: "/readyz", expectedStatus: http.StatusOK, expected: `"status":"ready"`},
+		{name: "wrong method", method: http.MethodPost, target: "/v1/score", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	s, _ := testServer(t)
+	handler := s.handler()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			var contentType string
+			if tc.body != nil {
+				body, contentType = tc.body(t)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.target, body)
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), tc.expected) {
+				t.Errorf("expected response to contain %q, got %s", tc.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeBatchOrder(t *testing.T) {
+	s, _ := testServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/score/batch", strings.NewReader(`{"purls": ["pkg:npm/express@4.18.2", "pkg:npm/debug@2.6.9"]}`))
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+
+	var response batchResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, r := range response.Results {
+		if r.Result == nil {
+			t.Fatalf("unexpected error for %s: %s", r.Purl, r.Error)
+		}
+		names = append(names, r.Result.Package.Name)
+	}
+
+	if strings.Join(names, ",") != "express,debug" {
+		t.Errorf("expected results in the order of the request, got %v", names)
+	}
+}
+
+func TestServeCache(t *testing.T) {
+	s, fake := testServer(t)
+	handler := s.handler()
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/score?purl=pkg:npm/express@4.18.2", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	if fake.Calls("GetVersion") != 2 {
+		t.Errorf("expected the 2 versions to be looked up once across requests, got %d calls to GetVersion", fake.Calls("GetVersion"))
+	}
+}
+
+func TestServeNotReady(t *testing.T) {
+	s, _ := testServer(t)
+	s.ready.Store(false)
+
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,141 @@
+// Package depscoreclient calls a DependencyScoreService (see depscorepb and depscoreserver),
+// so that services can evaluate packages without embedding the deps.dev client.
+package depscoreclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/depscorepb"
+)
+
+// Client evaluates packages with a remote DependencyScoreService.
+// Its methods mirror those of aggregdepscore.Evaluator.
+type Client struct {
+	client depscorepb.DependencyScoreServiceClient
+}
+
+// New creates a Client using conn, typically created with grpc.NewClient.
+func New(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: depscorepb.NewDependencyScoreServiceClient(conn)}
+}
+
+// EvaluateScore evaluates the score of a package.
+func (c *Client) EvaluateScore(ctx context.Context, p aggregdepscore.Package, opts ...grpc.CallOption) (float64, error) {
+	evaluation, err := c.Evaluate(ctx, p, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	return evaluation.Score, nil
+}
+
+// Evaluate evaluates a package; the evaluation has no tree (see Explain).
+func (c *Client) Evaluate(ctx context.Context, p aggregdepscore.Package, opts ...grpc.CallOption) (*aggregdepscore.Evaluation, error) {
+	resp, err := c.client.Score(ctx, &depscorepb.ScoreRequest{
+		Target: &depscorepb.ScoreRequest_Package{Package: depscorepb.NewPackage(p)},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetEvaluation().AsEvaluation(), nil
+}
+
+// EvaluateScores evaluates many packages in a single stream.
+// Results are returned in the order of packages;
+// an error evaluating one package, which is a gRPC status error, does not prevent the evaluation of the others.
+// The returned error is set if the stream failed.
+func (c *Client) EvaluateScores(ctx context.Context, packages []aggregdepscore.Package, opts ...grpc.CallOption) ([]aggregdepscore.ScoreResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.BatchScore(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sendErrs := make(chan error, 1)
+	go func() {
+		for _, p := range packages {
+			err := stream.Send(&depscorepb.ScoreRequest{
+				Target: &depscorepb.ScoreRequest_Package{Package: depscorepb.NewPackage(p)},
+			})
+			if err != nil {
+				// the error of the stream is returned by Recv
+				sendErrs <- nil
+				return
+			}
+		}
+
+		sendErrs <- stream.CloseSend()
+	}()
+
+	results := make([]aggregdepscore.ScoreResult, len(packages))
+	received := make([]bool, len(packages))
+	for i, p := range packages {
+		results[i] = aggregdepscore.ScoreResult{Index: i, Package: p}
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		i := int(resp.GetIndex())
+		if i < 0 || i >= len(results) {
+			return nil, fmt.Errorf("unexpected result index %d for %d packages", i, len(packages))
+		}
+		received[i] = true
+
+		if resp.GetError() != nil {
+			results[i].Err = status.Error(codes.Code(resp.GetError().GetCode()), resp.GetError().GetMessage())
+			continue
+		}
+
+		results[i].Evaluation = resp.GetEvaluation().AsEvaluation()
+	}
+
+	err = <-sendErrs
+	if err != nil {
+		return nil, fmt.Errorf("closing stream: %w", err)
+	}
+
+	for i := range results {
+		if !received[i] {
+			return nil, fmt.Errorf("missing result for package %d", i)
+		}
+	}
+
+	return results, nil
+}
+
+// Explain evaluates a package with its tree of evaluated packages, worst dependencies first;
+// dependencies deeper than maxDepth are left out of the tree (unlimited if 0).
+func (c *Client) Explain(ctx context.Context, p aggregdepscore.Package, maxDepth int, opts ...grpc.CallOption) (*aggregdepscore.Evaluation, error) {
+	resp, err := c.client.Explain(ctx, &depscorepb.ExplainRequest{
+		Target:   &depscorepb.ExplainRequest_Package{Package: depscorepb.NewPackage(p)},
+		MaxDepth: int32(maxDepth),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := resp.GetEvaluation().AsEvaluation()
+	if resp.GetTree() != nil {
+		evaluation.Tree = resp.GetTree().AsNode()
+	}
+
+	return evaluation, nil
+}
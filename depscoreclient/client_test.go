@@ -0,0 +1,181 @@
+package depscoreclient
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	api "deps.dev/api/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/depscorepb"
+	"github.com/DataDog/aggregated-dependency-score/depscoreserver"
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+var (
+	express = aggregdepscore.Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"}
+	debug   = aggregdepscore.Package{Ecosystem: "npm", Name: "debug", Version: "2.6.9"}
+	unknown = aggregdepscore.Package{Ecosystem: "npm", Name: "unknown", Version: "1.0.0"}
+)
+
+// testClient serves a DependencyScoreService backed by the deps.dev fake over an in-memory connection
+func testClient(t *testing.T, opts ...aggregdepscore.EvaluatorOption) *Client {
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/expressjs/express", 8)
+	fake.AddProject("github.com/debug-js/debug", 6)
+	fake.AddProject("github.com/vercel/ms", 5)
+	fake.AddVersion(api.System_NPM, "ms", "2.0.0", []string{"github.com/vercel/ms"})
+	fake.AddVersion(api.System_NPM, "debug", "2.6.9", []string{"github.com/debug-js/debug"},
+		&api.VersionKey{System: api.System_NPM, Name: "ms", Version: "2.0.0"})
+	fake.AddVersion(api.System_NPM, "express", "4.18.2", []string{"github.com/expressjs/express"},
+		&api.VersionKey{System: api.System_NPM, Name: "debug", Version: "2.6.9"})
+
+	depsDotDev, err := aggregdepscore.NewDepsDotDevClient(aggregdepscore.WithInsightsClient(fake))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluator, err := aggregdepscore.NewEvaluator(depsDotDev, depsDotDev, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	depscorepb.RegisterDependencyScoreServiceServer(server, depscoreserver.New(evaluator))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return New(conn)
+}
+
+func TestEvaluate(t *testing.T) {
+	c := testClient(t)
+
+	evaluation, err := c.Evaluate(context.Background(), express)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluation.Package != express {
+		t.Errorf("expected package %v, got %v", express, evaluation.Package)
+	}
+
+	if len(evaluation.DirectDependencies) != 1 || evaluation.DirectDependencies[0].Package != debug {
+		t.Errorf("expected debug as the only direct dependency, got %+v", evaluation.DirectDependencies)
+	}
+
+	expected := evaluation.Components.Intrinsic * math.Pow(evaluation.DirectDependencies[0].AggregatedTrustworthiness, 1.5)
+	if math.Abs(evaluation.Trustworthiness-expected) > 1e-9 {
+		t.Errorf("expected trustworthiness %f, got %f", expected, evaluation.Trustworthiness)
+	}
+
+	_, err = c.Evaluate(context.Background(), unknown)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown package, got %v", err)
+	}
+}
+
+func TestEvaluateScores(t *testing.T) {
+	c := testClient(t)
+
+	packages := []aggregdepscore.Package{express, unknown, debug, {Ecosystem: "npm"}}
+	results, err := c.EvaluateScores(context.Background(), packages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		expectedCode codes.Code
+	}{
+		{name: "express", expectedCode: codes.OK},
+		{name: "unknown", expectedCode: codes.NotFound},
+		{name: "debug", expectedCode: codes.OK},
+		{name: "invalid", expectedCode: codes.InvalidArgument},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := results[i]
+			if r.Index != i || r.Package != packages[i] {
+				t.Errorf("expected result %d for %v, got %d for %v", i, packages[i], r.Index, r.Package)
+			}
+
+			if status.Code(r.Err) != tc.expectedCode {
+				t.Errorf("expected code %s, got %v", tc.expectedCode, r.Err)
+			}
+
+			if tc.expectedCode == codes.OK && (r.Evaluation == nil || r.Evaluation.Package != packages[i]) {
+				t.Errorf("expected an evaluation of %v, got %+v", packages[i], r.Evaluation)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	testCases := []struct {
+		name         string
+		opts         []aggregdepscore.EvaluatorOption
+		maxDepth     int
+		expectedCode codes.Code
+		// expectedGrandchildren is the number of children of debug
+		expectedGrandchildren int
+	}{
+		{name: "full tree", opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}, expectedGrandchildren: 1},
+		{name: "collapsed", opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}, maxDepth: 1},
+		{name: "invalid depth", opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}, maxDepth: -1, expectedCode: codes.InvalidArgument},
+		{name: "without trees", expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := testClient(t, tc.opts...)
+
+			evaluation, err := c.Explain(context.Background(), express, tc.maxDepth)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("expected code %s, got %v", tc.expectedCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if evaluation.Tree == nil || evaluation.Tree.Package != express {
+				t.Fatalf("expected a tree rooted at express, got %+v", evaluation.Tree)
+			}
+
+			if len(evaluation.Tree.Children) != 1 {
+				t.Fatalf("expected 1 child, got %d", len(evaluation.Tree.Children))
+			}
+
+			child := evaluation.Tree.Children[0]
+			if child.Package != debug || child.Depth != 1 || math.Abs(child.Penalty-math.Pow(child.AggregatedTrustworthiness, 1.5)) > 1e-9 {
+				t.Errorf("unexpected child %+v", child)
+			}
+
+			if len(child.Children) != tc.expectedGrandchildren {
+				t.Errorf("expected %d children of debug, got %d", tc.expectedGrandchildren, len(child.Children))
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package depscorepb
+
+import (
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// NewPackage converts p to its protobuf message.
+func NewPackage(p aggregdepscore.Package) *Package {
+	return &Package{Ecosystem: p.Ecosystem, Name: p.Name, Version: p.Version}
+}
+
+// AsPackage converts x to a package; it is the zero package if x is nil.
+func (x *Package) AsPackage() aggregdepscore.Package {
+	return aggregdepscore.Package{Ecosystem: x.GetEcosystem(), Name: x.GetName(), Version: x.GetVersion()}
+}
+
+// NewEvaluation converts e to its protobuf message; the tree of e is left out (see NewNode).
+func NewEvaluation(e *aggregdepscore.Evaluation) *Evaluation {
+	x := &Evaluation{
+		Package:      NewPackage(e.Package),
+		Score:        e.Score,
+		RankingScore: e.RankingScore,
+		Trustworthiness: &Trustworthiness{
+			Aggregated:    e.Components.Aggregated,
+			Intrinsic:     e.Components.Intrinsic,
+			Transitive:    e.Components.Transitive,
+			LogAggregated: e.Components.LogAggregated,
+			LogIntrinsic:  e.Components.LogIntrinsic,
+			LogTransitive: e.Components.LogTransitive,
+		},
+		NbNodes:   int64(e.NbNodes),
+		Truncated: e.Truncated,
+	}
+
+	for _, dep := range e.DirectDependencies {
+		x.DirectDependencies = append(x.DirectDependencies, &DirectDependency{
+			Package:                      NewPackage(dep.Package),
+			Score:                        dep.Score,
+			AggregatedTrustworthiness:    dep.AggregatedTrustworthiness,
+			LogAggregatedTrustworthiness: dep.LogAggregatedTrustworthiness,
+		})
+	}
+
+	for i := range e.Fallbacks {
+		x.Fallbacks = append(x.Fallbacks, newFallback(&e.Fallbacks[i]))
+	}
+
+	for i := range e.Truncations {
+		x.Truncations = append(x.Truncations, newTruncation(&e.Truncations[i]))
+	}
+
+	for i := range e.BrokenCycles {
+		x.BrokenCycles = append(x.BrokenCycles, newCycle(&e.BrokenCycles[i]))
+	}
+
+	return x
+}
+
+// AsEvaluation converts x to an evaluation, without tree.
+func (x *Evaluation) AsEvaluation() *aggregdepscore.Evaluation {
+	t := x.GetTrustworthiness()
+
+	e := &aggregdepscore.Evaluation{
+		Package:            x.GetPackage().AsPackage(),
+		Score:              x.GetScore(),
+		Trustworthiness:    t.GetAggregated(),
+		LogTrustworthiness: t.GetLogAggregated(),
+		RankingScore:       x.GetRankingScore(),
+		Components: aggregdepscore.TrustworthinessComponents{
+			Aggregated:    t.GetAggregated(),
+			Intrinsic:     t.GetIntrinsic(),
+			Transitive:    t.GetTransitive(),
+			LogAggregated: t.GetLogAggregated(),
+			LogIntrinsic:  t.GetLogIntrinsic(),
+			LogTransitive: t.GetLogTransitive(),
+		},
+		NbNodes:   int(x.GetNbNodes()),
+		Truncated: x.GetTruncated(),
+	}
+
+	for _, dep := range x.GetDirectDependencies() {
+		e.DirectDependencies = append(e.DirectDependencies, aggregdepscore.DirectDependency{
+			Package:                      dep.GetPackage().AsPackage(),
+			Score:                        dep.GetScore(),
+			AggregatedTrustworthiness:    dep.GetAggregatedTrustworthiness(),
+			LogAggregatedTrustworthiness: dep.GetLogAggregatedTrustworthiness(),
+		})
+	}
+
+	for _, fallback := range x.GetFallbacks() {
+		e.Fallbacks = append(e.Fallbacks, *fallback.asFallback())
+	}
+
+	for _, truncation := range x.GetTruncations() {
+		e.Truncations = append(e.Truncations, *truncation.asTruncation())
+	}
+
+	for _, cycle := range x.GetBrokenCycles() {
+		e.BrokenCycles = append(e.BrokenCycles, *cycle.asCycle())
+	}
+
+	return e
+}
+
+// NewNode converts n and its descendants up to maxDepth (unlimited if 0) to protobuf messages;
+// the descendants beyond maxDepth are counted in NbCollapsedNodes.
+func NewNode(n *aggregdepscore.Node, maxDepth int) *Node {
+	x := &Node{
+		Package:                      NewPackage(n.Package),
+		Depth:                        int32(n.Depth),
+		IntrinsicTrustworthiness:     n.IntrinsicTrustworthiness,
+		AggregatedTrustworthiness:    n.AggregatedTrustworthiness,
+		LogAggregatedTrustworthiness: n.LogAggregatedTrustworthiness,
+		Penalty:                      n.Penalty,
+	}
+
+	if n.Fallback != nil {
+		x.Fallback = newFallback(n.Fallback)
+	}
+	if n.Truncation != nil {
+		x.Truncation = newTruncation(n.Truncation)
+	}
+	if n.Cycle != nil {
+		x.Cycle = newCycle(n.Cycle)
+	}
+
+	if maxDepth > 0 && n.Depth >= maxDepth {
+		for _, child := range n.Children {
+			child.Walk(func(*aggregdepscore.Node) bool {
+				x.NbCollapsedNodes++
+				return true
+			})
+		}
+
+		return x
+	}
+
+	for _, child := range n.Children {
+		x.Children = append(x.Children, NewNode(child, maxDepth))
+	}
+
+	return x
+}
+
+// AsNode converts x and its descendants to a tree of nodes.
+func (x *Node) AsNode() *aggregdepscore.Node {
+	n := &aggregdepscore.Node{
+		Package:                      x.GetPackage().AsPackage(),
+		Depth:                        int(x.GetDepth()),
+		IntrinsicTrustworthiness:     x.GetIntrinsicTrustworthiness(),
+		AggregatedTrustworthiness:    x.GetAggregatedTrustworthiness(),
+		LogAggregatedTrustworthiness: x.GetLogAggregatedTrustworthiness(),
+		Penalty:                      x.GetPenalty(),
+	}
+
+	if x.GetFallback() != nil {
+		n.Fallback = x.GetFallback().asFallback()
+	}
+	if x.GetTruncation() != nil {
+		n.Truncation = x.GetTruncation().asTruncation()
+	}
+	if x.GetCycle() != nil {
+		n.Cycle = x.GetCycle().asCycle()
+	}
+
+	for _, child := range x.GetChildren() {
+		n.Children = append(n.Children, child.AsNode())
+	}
+
+	return n
+}
+
+func newFallback(f *aggregdepscore.Fallback) *Fallback {
+	return &Fallback{
+		Package:                  NewPackage(f.Package),
+		IntrinsicTrustworthiness: f.IntrinsicTrustworthiness,
+		Reason:                   f.Reason,
+	}
+}
+
+func (x *Fallback) asFallback() *aggregdepscore.Fallback {
+	return &aggregdepscore.Fallback{
+		Package:                  x.GetPackage().AsPackage(),
+		IntrinsicTrustworthiness: x.GetIntrinsicTrustworthiness(),
+		Reason:                   x.GetReason(),
+	}
+}
+
+// truncation policies are numbered from 1 in protobuf messages, 0 being unspecified
+func newTruncation(t *aggregdepscore.Truncation) *Truncation {
+	return &Truncation{
+		Package: NewPackage(t.Package),
+		Depth:   int32(t.Depth),
+		Limit:   t.Limit,
+		Policy:  TruncationPolicy(t.Policy + 1),
+	}
+}
+
+func (x *Truncation) asTruncation() *aggregdepscore.Truncation {
+	return &aggregdepscore.Truncation{
+		Package: x.GetPackage().AsPackage(),
+		Depth:   int(x.GetDepth()),
+		Limit:   x.GetLimit(),
+		Policy:  aggregdepscore.TruncationPolicy(x.GetPolicy() - 1),
+	}
+}
+
+func newCycle(c *aggregdepscore.Cycle) *Cycle {
+	x := &Cycle{}
+	for _, p := range c.Packages {
+		x.Packages = append(x.Packages, NewPackage(p))
+	}
+
+	return x
+}
+
+func (x *Cycle) asCycle() *aggregdepscore.Cycle {
+	c := &aggregdepscore.Cycle{}
+	for _, p := range x.GetPackages() {
+		c.Packages = append(c.Packages, p.AsPackage())
+	}
+
+	return c
+}
@@ -0,0 +1,202 @@
+package depscorepb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+var (
+	express = aggregdepscore.Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"}
+	debug   = aggregdepscore.Package{Ecosystem: "npm", Name: "debug", Version: "2.6.9"}
+	ms      = aggregdepscore.Package{Ecosystem: "npm", Name: "ms", Version: "2.0.0"}
+)
+
+// roundTrip converts e to a protobuf message, encodes and decodes it, and converts it back
+func roundTrip(t *testing.T, e *aggregdepscore.Evaluation) *aggregdepscore.Evaluation {
+	data, err := proto.Marshal(NewEvaluation(e))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var x Evaluation
+	err = proto.Unmarshal(data, &x)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return x.AsEvaluation()
+}
+
+func TestEvaluationRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name       string
+		evaluation *aggregdepscore.Evaluation
+	}{
+		{
+			name:       "empty evaluation",
+			evaluation: &aggregdepscore.Evaluation{Package: express},
+		},
+		{
+			name: "complete evaluation",
+			evaluation: &aggregdepscore.Evaluation{
+				Package:            express,
+				Score:              0.25,
+				Trustworthiness:    0.6,
+				LogTrustworthiness: math.Log(0.6),
+				RankingScore:       0.25,
+				Components: aggregdepscore.TrustworthinessComponents{
+					Aggregated:    0.6,
+					Intrinsic:     0.9,
+					Transitive:    0.6 / 0.9,
+					LogAggregated: math.Log(0.6),
+					LogIntrinsic:  math.Log(0.9),
+					LogTransitive: math.Log(0.6 / 0.9),
+				},
+				DirectDependencies: []aggregdepscore.DirectDependency{
+					{Package: debug, Score: 0.5, AggregatedTrustworthiness: 0.8, LogAggregatedTrustworthiness: math.Log(0.8)},
+				},
+				BrokenCycles: []aggregdepscore.Cycle{{Packages: []aggregdepscore.Package{debug, ms, debug}}},
+				Fallbacks:    []aggregdepscore.Fallback{{Package: ms, IntrinsicTrustworthiness: 0.5, Reason: "no source repository"}},
+				NbNodes:      3,
+				Truncated:    true,
+				Truncations: []aggregdepscore.Truncation{
+					{Package: ms, Depth: 2, Limit: aggregdepscore.LimitDepth, Policy: aggregdepscore.TruncateFail},
+					{Package: ms, Depth: 2, Limit: aggregdepscore.LimitNodes, Policy: aggregdepscore.TruncateIntrinsicOnly},
+				},
+			},
+		},
+		{
+			// the aggregated trustworthiness of huge dependency graphs underflows to zero
+			name: "infinite logarithms",
+			evaluation: &aggregdepscore.Evaluation{
+				Package:            express,
+				LogTrustworthiness: math.Inf(-1),
+				RankingScore:       math.Inf(-1),
+				Components: aggregdepscore.TrustworthinessComponents{
+					Intrinsic:     0.9,
+					LogAggregated: math.Inf(-1),
+					LogIntrinsic:  math.Log(0.9),
+					LogTransitive: math.Inf(-1),
+				},
+				DirectDependencies: []aggregdepscore.DirectDependency{
+					{Package: debug, LogAggregatedTrustworthiness: math.Inf(-1)},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := roundTrip(t, tc.evaluation)
+			if !reflect.DeepEqual(actual, tc.evaluation) {
+				t.Errorf("expected %+v, got %+v", tc.evaluation, actual)
+			}
+		})
+	}
+}
+
+func TestEvaluationRoundTripNaN(t *testing.T) {
+	actual := roundTrip(t, &aggregdepscore.Evaluation{Package: express, Score: math.NaN(), RankingScore: math.NaN()})
+
+	if !math.IsNaN(actual.Score) || !math.IsNaN(actual.RankingScore) {
+		t.Errorf("expected NaN scores, got %f and %f", actual.Score, actual.RankingScore)
+	}
+}
+
+func TestEmptyLists(t *testing.T) {
+	// empty and nil lists are both encoded as no repeated field
+	actual := roundTrip(t, &aggregdepscore.Evaluation{
+		Package:            express,
+		DirectDependencies: []aggregdepscore.DirectDependency{},
+		Fallbacks:          []aggregdepscore.Fallback{},
+		Truncations:        []aggregdepscore.Truncation{},
+		BrokenCycles:       []aggregdepscore.Cycle{},
+	})
+
+	if len(actual.DirectDependencies) != 0 || len(actual.Fallbacks) != 0 || len(actual.Truncations) != 0 || len(actual.BrokenCycles) != 0 {
+		t.Errorf("expected no dependencies, fallbacks, truncations and cycles, got %+v", actual)
+	}
+	if actual.Truncated {
+		t.Errorf("expected the evaluation not to be truncated")
+	}
+}
+
+func TestNilMessages(t *testing.T) {
+	var p *Package
+	if actual := p.AsPackage(); actual != (aggregdepscore.Package{}) {
+		t.Errorf("expected the zero package, got %v", actual)
+	}
+
+	var x *Evaluation
+	if actual := x.AsEvaluation(); !reflect.DeepEqual(actual, &aggregdepscore.Evaluation{}) {
+		t.Errorf("expected an empty evaluation, got %+v", actual)
+	}
+}
+
+func TestNewNode(t *testing.T) {
+	// express -> debug -> ms -> express, with a fallback, a truncation and a cycle
+	tree := &aggregdepscore.Node{
+		Package:                      express,
+		IntrinsicTrustworthiness:     0.9,
+		AggregatedTrustworthiness:    0.6,
+		LogAggregatedTrustworthiness: math.Log(0.6),
+		Children: []*aggregdepscore.Node{{
+			Package:                      debug,
+			Depth:                        1,
+			IntrinsicTrustworthiness:     0.8,
+			AggregatedTrustworthiness:    0.7,
+			LogAggregatedTrustworthiness: math.Log(0.7),
+			Penalty:                      0.1,
+			Fallback:                     &aggregdepscore.Fallback{Package: debug, IntrinsicTrustworthiness: 0.8, Reason: "no scorecard"},
+			Children: []*aggregdepscore.Node{{
+				Package:    ms,
+				Depth:      2,
+				Truncation: &aggregdepscore.Truncation{Package: ms, Depth: 2, Limit: aggregdepscore.LimitCalls, Policy: aggregdepscore.TruncateAssumeTrustworthiness},
+				Children: []*aggregdepscore.Node{{
+					Package: express,
+					Depth:   3,
+					Cycle:   &aggregdepscore.Cycle{Packages: []aggregdepscore.Package{express, debug, ms, express}},
+				}},
+			}},
+		}},
+	}
+
+	testCases := []struct {
+		name              string
+		maxDepth          int
+		expectedDepth     int
+		expectedCollapsed int64
+	}{
+		{name: "unlimited", maxDepth: 0, expectedDepth: 3},
+		{name: "direct dependencies only", maxDepth: 1, expectedDepth: 1, expectedCollapsed: 2},
+		{name: "deeper than the tree", maxDepth: 5, expectedDepth: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x := NewNode(tree, tc.maxDepth)
+
+			depth := 0
+			var collapsed int64
+			for n := x; len(n.GetChildren()) > 0; n = n.GetChildren()[0] {
+				depth++
+				collapsed += n.GetChildren()[0].GetNbCollapsedNodes()
+			}
+			if depth != tc.expectedDepth {
+				t.Errorf("expected a depth of %d, got %d", tc.expectedDepth, depth)
+			}
+			if collapsed != tc.expectedCollapsed {
+				t.Errorf("expected %d collapsed nodes, got %d", tc.expectedCollapsed, collapsed)
+			}
+
+			if tc.maxDepth == 0 && !reflect.DeepEqual(x.AsNode(), tree) {
+				t.Errorf("expected %+v, got %+v", tree, x.AsNode())
+			}
+		})
+	}
+}
@@ -0,0 +1,1634 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: depscore.proto
+
+package depscorepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TruncationPolicy decides what happens to a package whose dependencies exceed a limit.
+type TruncationPolicy int32
+
+const (
+	TruncationPolicy_TRUNCATION_POLICY_UNSPECIFIED            TruncationPolicy = 0
+	TruncationPolicy_TRUNCATION_POLICY_FAIL                   TruncationPolicy = 1
+	TruncationPolicy_TRUNCATION_POLICY_ASSUME_TRUSTWORTHINESS TruncationPolicy = 2
+	TruncationPolicy_TRUNCATION_POLICY_INTRINSIC_ONLY         TruncationPolicy = 3
+)
+
+// Enum value maps for TruncationPolicy.
+var (
+	TruncationPolicy_name = map[int32]string{
+		0: "TRUNCATION_POLICY_UNSPECIFIED",
+		1: "TRUNCATION_POLICY_FAIL",
+		2: "TRUNCATION_POLICY_ASSUME_TRUSTWORTHINESS",
+		3: "TRUNCATION_POLICY_INTRINSIC_ONLY",
+	}
+	TruncationPolicy_value = map[string]int32{
+		"TRUNCATION_POLICY_UNSPECIFIED":            0,
+		"TRUNCATION_POLICY_FAIL":                   1,
+		"TRUNCATION_POLICY_ASSUME_TRUSTWORTHINESS": 2,
+		"TRUNCATION_POLICY_INTRINSIC_ONLY":         3,
+	}
+)
+
+func (x TruncationPolicy) Enum() *TruncationPolicy {
+	p := new(TruncationPolicy)
+	*p = x
+	return p
+}
+
+func (x TruncationPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TruncationPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_depscore_proto_enumTypes[0].Descriptor()
+}
+
+func (TruncationPolicy) Type() protoreflect.EnumType {
+	return &file_depscore_proto_enumTypes[0]
+}
+
+func (x TruncationPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TruncationPolicy.Descriptor instead.
+func (TruncationPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{0}
+}
+
+// Package is a version of a package.
+type Package struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Ecosystem is one of "npm", "pypi", "maven", "crates.io", "nuget" and "go".
+	Ecosystem string `protobuf:"bytes,1,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`
+	// Name follows the conventions of deps.dev, e.g. "group:artifact" for Maven.
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *Package) Reset() {
+	*x = Package{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Package) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Package) ProtoMessage() {}
+
+func (x *Package) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Package.ProtoReflect.Descriptor instead.
+func (*Package) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Package) GetEcosystem() string {
+	if x != nil {
+		return x.Ecosystem
+	}
+	return ""
+}
+
+func (x *Package) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Package) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type ScoreRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Target:
+	//	*ScoreRequest_Package
+	//	*ScoreRequest_Purl
+	Target isScoreRequest_Target `protobuf_oneof:"target"`
+}
+
+func (x *ScoreRequest) Reset() {
+	*x = ScoreRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreRequest) ProtoMessage() {}
+
+func (x *ScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreRequest.ProtoReflect.Descriptor instead.
+func (*ScoreRequest) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *ScoreRequest) GetTarget() isScoreRequest_Target {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (x *ScoreRequest) GetPackage() *Package {
+	if x, ok := x.GetTarget().(*ScoreRequest_Package); ok {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *ScoreRequest) GetPurl() string {
+	if x, ok := x.GetTarget().(*ScoreRequest_Purl); ok {
+		return x.Purl
+	}
+	return ""
+}
+
+type isScoreRequest_Target interface {
+	isScoreRequest_Target()
+}
+
+type ScoreRequest_Package struct {
+	Package *Package `protobuf:"bytes,1,opt,name=package,proto3,oneof"`
+}
+
+type ScoreRequest_Purl struct {
+	// Purl is a package-url such as "pkg:npm/express@4.18.2".
+	Purl string `protobuf:"bytes,2,opt,name=purl,proto3,oneof"`
+}
+
+func (*ScoreRequest_Package) isScoreRequest_Target() {}
+
+func (*ScoreRequest_Purl) isScoreRequest_Target() {}
+
+type ScoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Evaluation *Evaluation `protobuf:"bytes,1,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+}
+
+func (x *ScoreResponse) Reset() {
+	*x = ScoreResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreResponse) ProtoMessage() {}
+
+func (x *ScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreResponse.ProtoReflect.Descriptor instead.
+func (*ScoreResponse) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScoreResponse) GetEvaluation() *Evaluation {
+	if x != nil {
+		return x.Evaluation
+	}
+	return nil
+}
+
+type BatchScoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Index is the position of the request in the stream of requests.
+	Index int64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are assignable to Result:
+	//	*BatchScoreResponse_Evaluation
+	//	*BatchScoreResponse_Error
+	Result isBatchScoreResponse_Result `protobuf_oneof:"result"`
+}
+
+func (x *BatchScoreResponse) Reset() {
+	*x = BatchScoreResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchScoreResponse) ProtoMessage() {}
+
+func (x *BatchScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchScoreResponse.ProtoReflect.Descriptor instead.
+func (*BatchScoreResponse) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchScoreResponse) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (m *BatchScoreResponse) GetResult() isBatchScoreResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *BatchScoreResponse) GetEvaluation() *Evaluation {
+	if x, ok := x.GetResult().(*BatchScoreResponse_Evaluation); ok {
+		return x.Evaluation
+	}
+	return nil
+}
+
+func (x *BatchScoreResponse) GetError() *Error {
+	if x, ok := x.GetResult().(*BatchScoreResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isBatchScoreResponse_Result interface {
+	isBatchScoreResponse_Result()
+}
+
+type BatchScoreResponse_Evaluation struct {
+	Evaluation *Evaluation `protobuf:"bytes,2,opt,name=evaluation,proto3,oneof"`
+}
+
+type BatchScoreResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchScoreResponse_Evaluation) isBatchScoreResponse_Result() {}
+
+func (*BatchScoreResponse_Error) isBatchScoreResponse_Result() {}
+
+// Error is why a package could not be evaluated.
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Code is a gRPC status code.
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExplainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Target:
+	//	*ExplainRequest_Package
+	//	*ExplainRequest_Purl
+	Target isExplainRequest_Target `protobuf_oneof:"target"`
+	// MaxDepth is the depth beyond which dependencies are left out of the tree (unlimited if 0).
+	MaxDepth int32 `protobuf:"varint,3,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+}
+
+func (x *ExplainRequest) Reset() {
+	*x = ExplainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExplainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRequest) ProtoMessage() {}
+
+func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *ExplainRequest) GetTarget() isExplainRequest_Target {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (x *ExplainRequest) GetPackage() *Package {
+	if x, ok := x.GetTarget().(*ExplainRequest_Package); ok {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *ExplainRequest) GetPurl() string {
+	if x, ok := x.GetTarget().(*ExplainRequest_Purl); ok {
+		return x.Purl
+	}
+	return ""
+}
+
+func (x *ExplainRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+type isExplainRequest_Target interface {
+	isExplainRequest_Target()
+}
+
+type ExplainRequest_Package struct {
+	Package *Package `protobuf:"bytes,1,opt,name=package,proto3,oneof"`
+}
+
+type ExplainRequest_Purl struct {
+	// Purl is a package-url such as "pkg:npm/express@4.18.2".
+	Purl string `protobuf:"bytes,2,opt,name=purl,proto3,oneof"`
+}
+
+func (*ExplainRequest_Package) isExplainRequest_Target() {}
+
+func (*ExplainRequest_Purl) isExplainRequest_Target() {}
+
+type ExplainResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Evaluation *Evaluation `protobuf:"bytes,1,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	// Tree is the tree of evaluated packages, worst dependencies first.
+	Tree *Node `protobuf:"bytes,2,opt,name=tree,proto3" json:"tree,omitempty"`
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExplainResponse) GetEvaluation() *Evaluation {
+	if x != nil {
+		return x.Evaluation
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetTree() *Node {
+	if x != nil {
+		return x.Tree
+	}
+	return nil
+}
+
+type Evaluation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package *Package `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Score   float64  `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// RankingScore is equal to score when score is positive, but it is not clamped at zero.
+	RankingScore    float64          `protobuf:"fixed64,3,opt,name=ranking_score,json=rankingScore,proto3" json:"ranking_score,omitempty"`
+	Trustworthiness *Trustworthiness `protobuf:"bytes,4,opt,name=trustworthiness,proto3" json:"trustworthiness,omitempty"`
+	// DirectDependencies are the direct dependencies that were evaluated.
+	DirectDependencies []*DirectDependency `protobuf:"bytes,5,rep,name=direct_dependencies,json=directDependencies,proto3" json:"direct_dependencies,omitempty"`
+	// Fallbacks are the packages for which an intrinsic trustworthiness was assumed.
+	Fallbacks []*Fallback `protobuf:"bytes,6,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	// NbNodes is the number of package evaluations.
+	NbNodes int64 `protobuf:"varint,7,opt,name=nb_nodes,json=nbNodes,proto3" json:"nb_nodes,omitempty"`
+	// Truncated tells if some subtrees were not fully evaluated because of limits.
+	Truncated   bool          `protobuf:"varint,8,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Truncations []*Truncation `protobuf:"bytes,9,rep,name=truncations,proto3" json:"truncations,omitempty"`
+	// BrokenCycles are the dependency cycles that were ignored.
+	BrokenCycles []*Cycle `protobuf:"bytes,10,rep,name=broken_cycles,json=brokenCycles,proto3" json:"broken_cycles,omitempty"`
+}
+
+func (x *Evaluation) Reset() {
+	*x = Evaluation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Evaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evaluation) ProtoMessage() {}
+
+func (x *Evaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evaluation.ProtoReflect.Descriptor instead.
+func (*Evaluation) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Evaluation) GetPackage() *Package {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *Evaluation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Evaluation) GetRankingScore() float64 {
+	if x != nil {
+		return x.RankingScore
+	}
+	return 0
+}
+
+func (x *Evaluation) GetTrustworthiness() *Trustworthiness {
+	if x != nil {
+		return x.Trustworthiness
+	}
+	return nil
+}
+
+func (x *Evaluation) GetDirectDependencies() []*DirectDependency {
+	if x != nil {
+		return x.DirectDependencies
+	}
+	return nil
+}
+
+func (x *Evaluation) GetFallbacks() []*Fallback {
+	if x != nil {
+		return x.Fallbacks
+	}
+	return nil
+}
+
+func (x *Evaluation) GetNbNodes() int64 {
+	if x != nil {
+		return x.NbNodes
+	}
+	return 0
+}
+
+func (x *Evaluation) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *Evaluation) GetTruncations() []*Truncation {
+	if x != nil {
+		return x.Truncations
+	}
+	return nil
+}
+
+func (x *Evaluation) GetBrokenCycles() []*Cycle {
+	if x != nil {
+		return x.BrokenCycles
+	}
+	return nil
+}
+
+// Trustworthiness is the aggregated trustworthiness of a package
+// and the components it is computed from: aggregated = intrinsic × transitive.
+// Log values are natural logarithms.
+type Trustworthiness struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Aggregated    float64 `protobuf:"fixed64,1,opt,name=aggregated,proto3" json:"aggregated,omitempty"`
+	Intrinsic     float64 `protobuf:"fixed64,2,opt,name=intrinsic,proto3" json:"intrinsic,omitempty"`
+	Transitive    float64 `protobuf:"fixed64,3,opt,name=transitive,proto3" json:"transitive,omitempty"`
+	LogAggregated float64 `protobuf:"fixed64,4,opt,name=log_aggregated,json=logAggregated,proto3" json:"log_aggregated,omitempty"`
+	LogIntrinsic  float64 `protobuf:"fixed64,5,opt,name=log_intrinsic,json=logIntrinsic,proto3" json:"log_intrinsic,omitempty"`
+	LogTransitive float64 `protobuf:"fixed64,6,opt,name=log_transitive,json=logTransitive,proto3" json:"log_transitive,omitempty"`
+}
+
+func (x *Trustworthiness) Reset() {
+	*x = Trustworthiness{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Trustworthiness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trustworthiness) ProtoMessage() {}
+
+func (x *Trustworthiness) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trustworthiness.ProtoReflect.Descriptor instead.
+func (*Trustworthiness) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Trustworthiness) GetAggregated() float64 {
+	if x != nil {
+		return x.Aggregated
+	}
+	return 0
+}
+
+func (x *Trustworthiness) GetIntrinsic() float64 {
+	if x != nil {
+		return x.Intrinsic
+	}
+	return 0
+}
+
+func (x *Trustworthiness) GetTransitive() float64 {
+	if x != nil {
+		return x.Transitive
+	}
+	return 0
+}
+
+func (x *Trustworthiness) GetLogAggregated() float64 {
+	if x != nil {
+		return x.LogAggregated
+	}
+	return 0
+}
+
+func (x *Trustworthiness) GetLogIntrinsic() float64 {
+	if x != nil {
+		return x.LogIntrinsic
+	}
+	return 0
+}
+
+func (x *Trustworthiness) GetLogTransitive() float64 {
+	if x != nil {
+		return x.LogTransitive
+	}
+	return 0
+}
+
+type DirectDependency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package                      *Package `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Score                        float64  `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	AggregatedTrustworthiness    float64  `protobuf:"fixed64,3,opt,name=aggregated_trustworthiness,json=aggregatedTrustworthiness,proto3" json:"aggregated_trustworthiness,omitempty"`
+	LogAggregatedTrustworthiness float64  `protobuf:"fixed64,4,opt,name=log_aggregated_trustworthiness,json=logAggregatedTrustworthiness,proto3" json:"log_aggregated_trustworthiness,omitempty"`
+}
+
+func (x *DirectDependency) Reset() {
+	*x = DirectDependency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirectDependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectDependency) ProtoMessage() {}
+
+func (x *DirectDependency) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectDependency.ProtoReflect.Descriptor instead.
+func (*DirectDependency) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DirectDependency) GetPackage() *Package {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *DirectDependency) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *DirectDependency) GetAggregatedTrustworthiness() float64 {
+	if x != nil {
+		return x.AggregatedTrustworthiness
+	}
+	return 0
+}
+
+func (x *DirectDependency) GetLogAggregatedTrustworthiness() float64 {
+	if x != nil {
+		return x.LogAggregatedTrustworthiness
+	}
+	return 0
+}
+
+type Fallback struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package *Package `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	// IntrinsicTrustworthiness is the value that was assumed.
+	IntrinsicTrustworthiness float64 `protobuf:"fixed64,2,opt,name=intrinsic_trustworthiness,json=intrinsicTrustworthiness,proto3" json:"intrinsic_trustworthiness,omitempty"`
+	Reason                   string  `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *Fallback) Reset() {
+	*x = Fallback{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fallback) ProtoMessage() {}
+
+func (x *Fallback) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fallback.ProtoReflect.Descriptor instead.
+func (*Fallback) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Fallback) GetPackage() *Package {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *Fallback) GetIntrinsicTrustworthiness() float64 {
+	if x != nil {
+		return x.IntrinsicTrustworthiness
+	}
+	return 0
+}
+
+func (x *Fallback) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Truncation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package *Package `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Depth   int32    `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	// Limit is one of "depth", "nodes" and "calls".
+	Limit  string           `protobuf:"bytes,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Policy TruncationPolicy `protobuf:"varint,4,opt,name=policy,proto3,enum=depscore.v1.TruncationPolicy" json:"policy,omitempty"`
+}
+
+func (x *Truncation) Reset() {
+	*x = Truncation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Truncation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Truncation) ProtoMessage() {}
+
+func (x *Truncation) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Truncation.ProtoReflect.Descriptor instead.
+func (*Truncation) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Truncation) GetPackage() *Package {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *Truncation) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *Truncation) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *Truncation) GetPolicy() TruncationPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return TruncationPolicy_TRUNCATION_POLICY_UNSPECIFIED
+}
+
+type Cycle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Packages []*Package `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *Cycle) Reset() {
+	*x = Cycle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Cycle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cycle) ProtoMessage() {}
+
+func (x *Cycle) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cycle.ProtoReflect.Descriptor instead.
+func (*Cycle) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Cycle) GetPackages() []*Package {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+// Node is an evaluated package in the tree of an evaluation.
+type Node struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package                      *Package `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Depth                        int32    `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	IntrinsicTrustworthiness     float64  `protobuf:"fixed64,3,opt,name=intrinsic_trustworthiness,json=intrinsicTrustworthiness,proto3" json:"intrinsic_trustworthiness,omitempty"`
+	AggregatedTrustworthiness    float64  `protobuf:"fixed64,4,opt,name=aggregated_trustworthiness,json=aggregatedTrustworthiness,proto3" json:"aggregated_trustworthiness,omitempty"`
+	LogAggregatedTrustworthiness float64  `protobuf:"fixed64,5,opt,name=log_aggregated_trustworthiness,json=logAggregatedTrustworthiness,proto3" json:"log_aggregated_trustworthiness,omitempty"`
+	// Penalty is the factor this node multiplies the aggregated trustworthiness of its parent by.
+	Penalty    float64     `protobuf:"fixed64,6,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	Fallback   *Fallback   `protobuf:"bytes,7,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	Truncation *Truncation `protobuf:"bytes,8,opt,name=truncation,proto3" json:"truncation,omitempty"`
+	// Cycle is set if the package was not evaluated because it closes a dependency cycle.
+	Cycle    *Cycle  `protobuf:"bytes,9,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Children []*Node `protobuf:"bytes,10,rep,name=children,proto3" json:"children,omitempty"`
+	// NbCollapsedNodes is the number of descendants left out because of max_depth.
+	NbCollapsedNodes int64 `protobuf:"varint,11,opt,name=nb_collapsed_nodes,json=nbCollapsedNodes,proto3" json:"nb_collapsed_nodes,omitempty"`
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depscore_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_depscore_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_depscore_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Node) GetPackage() *Package {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *Node) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *Node) GetIntrinsicTrustworthiness() float64 {
+	if x != nil {
+		return x.IntrinsicTrustworthiness
+	}
+	return 0
+}
+
+func (x *Node) GetAggregatedTrustworthiness() float64 {
+	if x != nil {
+		return x.AggregatedTrustworthiness
+	}
+	return 0
+}
+
+func (x *Node) GetLogAggregatedTrustworthiness() float64 {
+	if x != nil {
+		return x.LogAggregatedTrustworthiness
+	}
+	return 0
+}
+
+func (x *Node) GetPenalty() float64 {
+	if x != nil {
+		return x.Penalty
+	}
+	return 0
+}
+
+func (x *Node) GetFallback() *Fallback {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+func (x *Node) GetTruncation() *Truncation {
+	if x != nil {
+		return x.Truncation
+	}
+	return nil
+}
+
+func (x *Node) GetCycle() *Cycle {
+	if x != nil {
+		return x.Cycle
+	}
+	return nil
+}
+
+func (x *Node) GetChildren() []*Node {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *Node) GetNbCollapsedNodes() int64 {
+	if x != nil {
+		return x.NbCollapsedNodes
+	}
+	return 0
+}
+
+var File_depscore_proto protoreflect.FileDescriptor
+
+var file_depscore_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0b, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x55, 0x0a,
+	0x07, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x63, 0x6f, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x63, 0x6f,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x60, 0x0a, 0x0c, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x70, 0x75, 0x72, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x75, 0x72, 0x6c, 0x42, 0x08, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x48, 0x0a, 0x0d, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x9b, 0x01, 0x0a, 0x12, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x39, 0x0a,
+	0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x35,
+	0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x7f, 0x0a, 0x0e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x70, 0x75, 0x72,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x75, 0x72, 0x6c, 0x12,
+	0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x42, 0x08, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x71, 0x0a, 0x0f, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x65, 0x76, 0x61,
+	0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x61, 0x6c,
+	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x72, 0x65, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x6f, 0x64, 0x65, 0x52, 0x04, 0x74, 0x72, 0x65, 0x65, 0x22, 0xf1, 0x03, 0x0a, 0x0a, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52,
+	0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x72, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x12, 0x46, 0x0a, 0x0f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74,
+	0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x64,
+	0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x75, 0x73, 0x74,
+	0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x52, 0x0f, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x4e, 0x0a, 0x13, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x44, 0x65, 0x70,
+	0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x12, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x44,
+	0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x09, 0x66,
+	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x09, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x73,
+	0x12, 0x19, 0x0a, 0x08, 0x6e, 0x62, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x6e, 0x62, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72, 0x75,
+	0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x75,
+	0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x0d, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63,
+	0x79, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52,
+	0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x22, 0xe2, 0x01,
+	0x0a, 0x0f, 0x54, 0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x12,
+	0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x25, 0x0a, 0x0e, 0x6c, 0x6f, 0x67, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e,
+	0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6c,
+	0x6f, 0x67, 0x49, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x12, 0x25, 0x0a, 0x0e, 0x6c,
+	0x6f, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x76, 0x65, 0x22, 0xdd, 0x01, 0x0a, 0x10, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x44, 0x65, 0x70,
+	0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07,
+	0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x3d, 0x0a,
+	0x1a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x19, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x54, 0x72, 0x75,
+	0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x44, 0x0a, 0x1e,
+	0x6c, 0x6f, 0x67, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x1c, 0x6c, 0x6f, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65,
+	0x73, 0x73, 0x22, 0x8f, 0x01, 0x0a, 0x08, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12,
+	0x2e, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
+	0x3b, 0x0a, 0x19, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x5f, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x18, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x54, 0x72, 0x75,
+	0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x0a, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12,
+	0x35, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1d, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72,
+	0x75, 0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x39, 0x0a, 0x05, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12,
+	0x30, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x73, 0x22, 0x9b, 0x04, 0x0a, 0x04, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65,
+	0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68,
+	0x12, 0x3b, 0x0a, 0x19, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x5f, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x18, 0x69, 0x6e, 0x74, 0x72, 0x69, 0x6e, 0x73, 0x69, 0x63, 0x54, 0x72,
+	0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x3d, 0x0a,
+	0x1a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x19, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x54, 0x72, 0x75,
+	0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x44, 0x0a, 0x1e,
+	0x6c, 0x6f, 0x67, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x1c, 0x6c, 0x6f, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x72, 0x75, 0x73, 0x74, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x69, 0x6e, 0x65,
+	0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x31, 0x0a, 0x08,
+	0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12,
+	0x37, 0x0a, 0x0a, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x74, 0x72,
+	0x75, 0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x05, 0x63, 0x79, 0x63, 0x6c,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x05, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65,
+	0x6e, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x62, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x70, 0x73, 0x65,
+	0x64, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6e,
+	0x62, 0x43, 0x6f, 0x6c, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x2a,
+	0xa5, 0x01, 0x0a, 0x10, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x1d, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x54, 0x52, 0x55, 0x4e, 0x43,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x10, 0x01, 0x12, 0x2c, 0x0a, 0x28, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x41, 0x53, 0x53, 0x55, 0x4d, 0x45, 0x5f,
+	0x54, 0x52, 0x55, 0x53, 0x54, 0x57, 0x4f, 0x52, 0x54, 0x48, 0x49, 0x4e, 0x45, 0x53, 0x53, 0x10,
+	0x02, 0x12, 0x24, 0x0a, 0x20, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x52, 0x49, 0x4e, 0x53, 0x49, 0x43,
+	0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x03, 0x32, 0xec, 0x01, 0x0a, 0x16, 0x44, 0x65, 0x70, 0x65,
+	0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x3e, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x19, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0a, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x19, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01,
+	0x12, 0x44, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x12, 0x1b, 0x2e, 0x64, 0x65,
+	0x70, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x65, 0x70, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x44, 0x61, 0x74, 0x61, 0x44, 0x6f, 0x67, 0x2f, 0x61, 0x67, 0x67,
+	0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x2d, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e,
+	0x63, 0x79, 0x2d, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x64, 0x65, 0x70, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_depscore_proto_rawDescOnce sync.Once
+	file_depscore_proto_rawDescData = file_depscore_proto_rawDesc
+)
+
+func file_depscore_proto_rawDescGZIP() []byte {
+	file_depscore_proto_rawDescOnce.Do(func() {
+		file_depscore_proto_rawDescData = protoimpl.X.CompressGZIP(file_depscore_proto_rawDescData)
+	})
+	return file_depscore_proto_rawDescData
+}
+
+var file_depscore_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_depscore_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_depscore_proto_goTypes = []any{
+	(TruncationPolicy)(0),      // 0: depscore.v1.TruncationPolicy
+	(*Package)(nil),            // 1: depscore.v1.Package
+	(*ScoreRequest)(nil),       // 2: depscore.v1.ScoreRequest
+	(*ScoreResponse)(nil),      // 3: depscore.v1.ScoreResponse
+	(*BatchScoreResponse)(nil), // 4: depscore.v1.BatchScoreResponse
+	(*Error)(nil),              // 5: depscore.v1.Error
+	(*ExplainRequest)(nil),     // 6: depscore.v1.ExplainRequest
+	(*ExplainResponse)(nil),    // 7: depscore.v1.ExplainResponse
+	(*Evaluation)(nil),         // 8: depscore.v1.Evaluation
+	(*Trustworthiness)(nil),    // 9: depscore.v1.Trustworthiness
+	(*DirectDependency)(nil),   // 10: depscore.v1.DirectDependency
+	(*Fallback)(nil),           // 11: depscore.v1.Fallback
+	(*Truncation)(nil),         // 12: depscore.v1.Truncation
+	(*Cycle)(nil),              // 13: depscore.v1.Cycle
+	(*Node)(nil),               // 14: depscore.v1.Node
+}
+var file_depscore_proto_depIdxs = []int32{
+	1,  // 0: depscore.v1.ScoreRequest.package:type_name -> depscore.v1.Package
+	8,  // 1: depscore.v1.ScoreResponse.evaluation:type_name -> depscore.v1.Evaluation
+	8,  // 2: depscore.v1.BatchScoreResponse.evaluation:type_name -> depscore.v1.Evaluation
+	5,  // 3: depscore.v1.BatchScoreResponse.error:type_name -> depscore.v1.Error
+	1,  // 4: depscore.v1.ExplainRequest.package:type_name -> depscore.v1.Package
+	8,  // 5: depscore.v1.ExplainResponse.evaluation:type_name -> depscore.v1.Evaluation
+	14, // 6: depscore.v1.ExplainResponse.tree:type_name -> depscore.v1.Node
+	1,  // 7: depscore.v1.Evaluation.package:type_name -> depscore.v1.Package
+	9,  // 8: depscore.v1.Evaluation.trustworthiness:type_name -> depscore.v1.Trustworthiness
+	10, // 9: depscore.v1.Evaluation.direct_dependencies:type_name -> depscore.v1.DirectDependency
+	11, // 10: depscore.v1.Evaluation.fallbacks:type_name -> depscore.v1.Fallback
+	12, // 11: depscore.v1.Evaluation.truncations:type_name -> depscore.v1.Truncation
+	13, // 12: depscore.v1.Evaluation.broken_cycles:type_name -> depscore.v1.Cycle
+	1,  // 13: depscore.v1.DirectDependency.package:type_name -> depscore.v1.Package
+	1,  // 14: depscore.v1.Fallback.package:type_name -> depscore.v1.Package
+	1,  // 15: depscore.v1.Truncation.package:type_name -> depscore.v1.Package
+	0,  // 16: depscore.v1.Truncation.policy:type_name -> depscore.v1.TruncationPolicy
+	1,  // 17: depscore.v1.Cycle.packages:type_name -> depscore.v1.Package
+	1,  // 18: depscore.v1.Node.package:type_name -> depscore.v1.Package
+	11, // 19: depscore.v1.Node.fallback:type_name -> depscore.v1.Fallback
+	12, // 20: depscore.v1.Node.truncation:type_name -> depscore.v1.Truncation
+	13, // 21: depscore.v1.Node.cycle:type_name -> depscore.v1.Cycle
+	14, // 22: depscore.v1.Node.children:type_name -> depscore.v1.Node
+	2,  // 23: depscore.v1.DependencyScoreService.Score:input_type -> depscore.v1.ScoreRequest
+	2,  // 24: depscore.v1.DependencyScoreService.BatchScore:input_type -> depscore.v1.ScoreRequest
+	6,  // 25: depscore.v1.DependencyScoreService.Explain:input_type -> depscore.v1.ExplainRequest
+	3,  // 26: depscore.v1.DependencyScoreService.Score:output_type -> depscore.v1.ScoreResponse
+	4,  // 27: depscore.v1.DependencyScoreService.BatchScore:output_type -> depscore.v1.BatchScoreResponse
+	7,  // 28: depscore.v1.DependencyScoreService.Explain:output_type -> depscore.v1.ExplainResponse
+	26, // [26:29] is the sub-list for method output_type
+	23, // [23:26] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_depscore_proto_init() }
+func file_depscore_proto_init() {
+	if File_depscore_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_depscore_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Package); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ScoreRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ScoreResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchScoreResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ExplainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ExplainResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Evaluation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Trustworthiness); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*DirectDependency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Fallback); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Truncation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*Cycle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depscore_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*Node); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_depscore_proto_msgTypes[1].OneofWrappers = []any{
+		(*ScoreRequest_Package)(nil),
+		(*ScoreRequest_Purl)(nil),
+	}
+	file_depscore_proto_msgTypes[3].OneofWrappers = []any{
+		(*BatchScoreResponse_Evaluation)(nil),
+		(*BatchScoreResponse_Error)(nil),
+	}
+	file_depscore_proto_msgTypes[5].OneofWrappers = []any{
+		(*ExplainRequest_Package)(nil),
+		(*ExplainRequest_Purl)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_depscore_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_depscore_proto_goTypes,
+		DependencyIndexes: file_depscore_proto_depIdxs,
+		EnumInfos:         file_depscore_proto_enumTypes,
+		MessageInfos:      file_depscore_proto_msgTypes,
+	}.Build()
+	File_depscore_proto = out.File
+	file_depscore_proto_rawDesc = nil
+	file_depscore_proto_goTypes = nil
+	file_depscore_proto_depIdxs = nil
+}
@@ -0,0 +1,5 @@
+// Package depscorepb contains the protobuf messages and the gRPC stubs of the DependencyScoreService
+// (see depscore.proto), and conversions from and to the types of the aggregdepscore package.
+package depscorepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative depscore.proto
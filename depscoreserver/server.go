@@ -0,0 +1,241 @@
+// Package depscoreserver implements the DependencyScoreService gRPC service (see depscorepb)
+// with an Evaluator.
+package depscoreserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/depscorepb"
+)
+
+// Server evaluates the packages requested through gRPC.
+//
+// Explain requires the evaluator to be created with aggregdepscore.WithEvaluationTree.
+type Server struct {
+	depscorepb.UnimplementedDependencyScoreServiceServer
+
+	evaluator *aggregdepscore.Evaluator
+	logger    *slog.Logger
+}
+
+// compile-time interface checks
+var _ depscorepb.DependencyScoreServiceServer = &Server{}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger sets the logger of failed evaluations; the default discards logs.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// New creates a Server; register it with depscorepb.RegisterDependencyScoreServiceServer.
+func New(evaluator *aggregdepscore.Evaluator, opts ...Option) *Server {
+	s := &Server{
+		evaluator: evaluator,
+		logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Score evaluates a package.
+func (s *Server) Score(ctx context.Context, req *depscorepb.ScoreRequest) (*depscorepb.ScoreResponse, error) {
+	p, err := requestedPackage(req.GetPackage(), req.GetPurl())
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation, err := s.evaluator.Evaluate(ctx, p)
+	if err != nil {
+		return nil, s.evaluationError(p, err)
+	}
+
+	return &depscorepb.ScoreResponse{Evaluation: depscorepb.NewEvaluation(evaluation)}, nil
+}
+
+// BatchScore evaluates the packages streamed by the client, concurrently,
+// sharing the lookups of their common dependencies (see Evaluator.EvaluateScoresStream).
+// Invalid requests and failed evaluations get an error response and do not end the stream.
+func (s *Server) BatchScore(stream depscorepb.DependencyScoreService_BatchScoreServer) error {
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
+
+	in := make(chan aggregdepscore.Package)
+	invalid := make(chan *depscorepb.BatchScoreResponse)
+
+	// requestIndexes maps the positions of the packages sent to the evaluator
+	// to the positions of their requests in the stream
+	var mutex sync.Mutex
+	var requestIndexes []int64
+
+	go func() {
+		defer close(in)
+		defer close(invalid)
+
+		for index := int64(0); ; index++ {
+			req, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				cancel(err)
+				return
+			}
+
+			p, err := requestedPackage(req.GetPackage(), req.GetPurl())
+			if err != nil {
+				select {
+				case invalid <- &depscorepb.BatchScoreResponse{Index: index, Result: &depscorepb.BatchScoreResponse_Error{Error: newError(err)}}:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			mutex.Lock()
+			requestIndexes = append(requestIndexes, index)
+			mutex.Unlock()
+
+			select {
+			case in <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := s.evaluator.EvaluateScoresStream(ctx, in)
+	for results != nil || invalid != nil {
+		var response *depscorepb.BatchScoreResponse
+
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+
+			mutex.Lock()
+			index := requestIndexes[result.Index]
+			mutex.Unlock()
+
+			response = &depscorepb.BatchScoreResponse{Index: index}
+			if result.Err != nil {
+				response.Result = &depscorepb.BatchScoreResponse_Error{Error: newError(s.evaluationError(result.Package, result.Err))}
+			} else {
+				response.Result = &depscorepb.BatchScoreResponse_Evaluation{Evaluation: depscorepb.NewEvaluation(result.Evaluation)}
+			}
+		case r, ok := <-invalid:
+			if !ok {
+				invalid = nil
+				continue
+			}
+			response = r
+		}
+
+		err := stream.Send(response)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := context.Cause(ctx)
+	if _, ok := status.FromError(err); ok {
+		// nil, or the error receiving requests
+		return err
+	}
+
+	return status.FromContextError(err).Err()
+}
+
+// Explain evaluates a package and returns the tree of evaluated packages, worst dependencies first.
+func (s *Server) Explain(ctx context.Context, req *depscorepb.ExplainRequest) (*depscorepb.ExplainResponse, error) {
+	p, err := requestedPackage(req.GetPackage(), req.GetPurl())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetMaxDepth() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max depth %d", req.GetMaxDepth())
+	}
+
+	evaluation, err := s.evaluator.Evaluate(ctx, p)
+	if err != nil {
+		return nil, s.evaluationError(p, err)
+	}
+
+	if evaluation.Tree == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the server does not build evaluation trees")
+	}
+
+	evaluation.Tree.SortWorstFirst()
+
+	return &depscorepb.ExplainResponse{
+		Evaluation: depscorepb.NewEvaluation(evaluation),
+		Tree:       depscorepb.NewNode(evaluation.Tree, int(req.GetMaxDepth())),
+	}, nil
+}
+
+// requestedPackage is the package of a request, given either as a message or as a package-url
+func requestedPackage(p *depscorepb.Package, purl string) (aggregdepscore.Package, error) {
+	switch {
+	case p != nil:
+		if p.GetEcosystem() == "" || p.GetName() == "" || p.GetVersion() == "" {
+			return aggregdepscore.Package{}, status.Error(codes.InvalidArgument, "ecosystem, name and version are required")
+		}
+		return p.AsPackage(), nil
+	case purl != "":
+		parsed, err := aggregdepscore.ParsePurl(purl)
+		if err != nil {
+			return aggregdepscore.Package{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return parsed, nil
+	default:
+		return aggregdepscore.Package{}, status.Error(codes.InvalidArgument, "a package or a purl is required")
+	}
+}
+
+// evaluationError converts an evaluation error to a gRPC status error
+func (s *Server) evaluationError(p aggregdepscore.Package, err error) error {
+	var limitExceeded *aggregdepscore.ErrLimitExceeded
+
+	code := status.Code(err)
+	switch {
+	case errors.As(err, &limitExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+
+	if code != codes.NotFound && code != codes.InvalidArgument && code != codes.Canceled {
+		s.logger.Warn("evaluation failed",
+			slog.String("ecosystem", p.Ecosystem),
+			slog.String("name", p.Name),
+			slog.String("version", p.Version),
+			slog.String("error", err.Error()))
+	}
+
+	return status.Errorf(code, "evaluating %s %s@%s: %v", p.Ecosystem, p.Name, p.Version, err)
+}
+
+func newError(err error) *depscorepb.Error {
+	st := status.Convert(err)
+	return &depscorepb.Error{Code: int32(st.Code()), Message: st.Message()}
+}
@@ -0,0 +1,175 @@
+package depscoreserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+	"github.com/DataDog/aggregated-dependency-score/depscorepb"
+)
+
+var (
+	express = aggregdepscore.Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"}
+	debug   = aggregdepscore.Package{Ecosystem: "npm", Name: "debug", Version: "2.6.9"}
+)
+
+// testLookups evaluates express, which depends on debug, and fails for other packages
+type testLookups struct{}
+
+func (testLookups) EvaluateIntrinsicTrustworthiness(ctx context.Context, p aggregdepscore.Package) (float64, error) {
+	switch p {
+	case express:
+		return 0.9, nil
+	case debug:
+		return 0.8, nil
+	default:
+		return 0, status.Errorf(codes.NotFound, "unknown package %s", p)
+	}
+}
+
+func (testLookups) GetDirectDependencies(ctx context.Context, p aggregdepscore.Package) ([]aggregdepscore.Package, error) {
+	if p == express {
+		return []aggregdepscore.Package{debug}, nil
+	}
+
+	return nil, nil
+}
+
+func testServer(t *testing.T, opts ...aggregdepscore.EvaluatorOption) *Server {
+	evaluator, err := aggregdepscore.NewEvaluator(testLookups{}, testLookups{}, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return New(evaluator)
+}
+
+func TestEvaluationError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{name: "limit exceeded", err: fmt.Errorf("evaluating debug: %w", &aggregdepscore.ErrLimitExceeded{Limit: aggregdepscore.LimitNodes, Value: 1, Package: debug}), expected: codes.ResourceExhausted},
+		{name: "deadline exceeded", err: fmt.Errorf("evaluating debug: %w", context.DeadlineExceeded), expected: codes.DeadlineExceeded},
+		{name: "canceled", err: fmt.Errorf("evaluating debug: %w", context.Canceled), expected: codes.Canceled},
+		{name: "status error", err: fmt.Errorf("evaluating debug: %w", status.Error(codes.NotFound, "unknown package")), expected: codes.NotFound},
+		{name: "other error", err: errors.New("no scorecard"), expected: codes.Unknown},
+	}
+
+	s := New(nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.evaluationError(express, tc.err)
+
+			if status.Code(err) != tc.expected {
+				t.Errorf("expected code %s, got %s", tc.expected, status.Code(err))
+			}
+
+			expectedMessage := "evaluating npm express@4.18.2: " + tc.err.Error()
+			if status.Convert(err).Message() != expectedMessage {
+				t.Errorf("expected message %q, got %q", expectedMessage, status.Convert(err).Message())
+			}
+
+			// errors in batch responses keep the code and message
+			e := newError(err)
+			if codes.Code(e.GetCode()) != tc.expected || e.GetMessage() != expectedMessage {
+				t.Errorf("expected error %s %q, got %s %q", tc.expected, expectedMessage, codes.Code(e.GetCode()), e.GetMessage())
+			}
+		})
+	}
+}
+
+func TestRequestedPackage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pkg      *depscorepb.Package
+		purl     string
+		expected aggregdepscore.Package
+		code     codes.Code
+	}{
+		{name: "package", pkg: depscorepb.NewPackage(express), expected: express},
+		{name: "purl", purl: "pkg:npm/express@4.18.2", expected: express},
+		{name: "package rather than purl", pkg: depscorepb.NewPackage(express), purl: "pkg:npm/debug@2.6.9", expected: express},
+		{name: "missing version", pkg: &depscorepb.Package{Ecosystem: "npm", Name: "express"}, code: codes.InvalidArgument},
+		{name: "invalid purl", purl: "npm/express", code: codes.InvalidArgument},
+		{name: "neither package nor purl", code: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := requestedPackage(tc.pkg, tc.purl)
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected code %s, got %v", tc.code, err)
+			}
+
+			if p != tc.expected {
+				t.Errorf("expected package %v, got %v", tc.expected, p)
+			}
+		})
+	}
+}
+
+func TestScoreErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pkg      aggregdepscore.Package
+		opts     []aggregdepscore.EvaluatorOption
+		expected codes.Code
+	}{
+		{name: "unknown package", pkg: aggregdepscore.Package{Ecosystem: "npm", Name: "unknown", Version: "1.0.0"}, expected: codes.NotFound},
+		{name: "limit exceeded", pkg: express, opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithLimits(aggregdepscore.Limits{MaxNodes: 1})}, expected: codes.ResourceExhausted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testServer(t, tc.opts...)
+
+			_, err := s.Score(context.Background(), &depscorepb.ScoreRequest{Target: &depscorepb.ScoreRequest_Package{Package: depscorepb.NewPackage(tc.pkg)}})
+			if status.Code(err) != tc.expected {
+				t.Errorf("expected code %s, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []aggregdepscore.EvaluatorOption
+		maxDepth int32
+		expected codes.Code
+	}{
+		{name: "negative max depth", opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}, maxDepth: -1, expected: codes.InvalidArgument},
+		{name: "no evaluation tree", expected: codes.FailedPrecondition},
+		{name: "evaluation tree", opts: []aggregdepscore.EvaluatorOption{aggregdepscore.WithEvaluationTree()}, expected: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testServer(t, tc.opts...)
+
+			response, err := s.Explain(context.Background(), &depscorepb.ExplainRequest{
+				Target:   &depscorepb.ExplainRequest_Purl{Purl: "pkg:npm/express@4.18.2"},
+				MaxDepth: tc.maxDepth,
+			})
+			if status.Code(err) != tc.expected {
+				t.Fatalf("expected code %s, got %v", tc.expected, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			children := response.GetTree().GetChildren()
+			if len(children) != 1 || children[0].GetPackage().AsPackage() != debug {
+				t.Errorf("expected debug as the only child of the tree, got %v", response.GetTree())
+			}
+		})
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
 
 	api "deps.dev/api/v3"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -14,11 +17,108 @@ import (
 type client struct {
 	depsdotdev api.InsightsClient
 	converter  ScoreTrustworthinessConverter
+	goVanity   *GoVanityResolver
+
+	repositorySelector RepositorySelector
+	onDiscard          func(p Package, selection RepositorySelection)
+	logger             *slog.Logger
+	tracerProvider     trace.TracerProvider
+	metrics            *Metrics
+	// retry is nil if RPCs are not retried
+	retry *RetryPolicy
+	// rateLimit is the maximum number of RPCs per second, unlimited if 0
+	rateLimit float64
+
+	// projects are cached by repository
+	// because many packages can be published from the same repository
+	projectCacheMutex sync.Mutex
+	projectCache      map[string]*api.Project
+
+	// discardsReported are the packages whose discarded repositories were reported,
+	// because the repository of a package is looked up several times
+	// (for its intrinsic trustworthiness, overrides and trust domains)
+	discardsReportedMutex sync.Mutex
+	discardsReported      map[Package]bool
+}
+
+// DepsDotDevOption configures the client created by NewDepsDotDevClient.
+type DepsDotDevOption func(*client)
+
+// WithInsightsClient makes the client use the given deps.dev API client
+// instead of connecting to api.deps.dev,
+// for instance to go through a proxy or to use a fake in tests.
+func WithInsightsClient(insights api.InsightsClient) DepsDotDevOption {
+	return func(c *client) {
+		c.depsdotdev = insights
+	}
+}
+
+// WithGoVanityResolver sets the resolver used to find the source repository
+// of Go modules with a vanity import path that deps.dev cannot map to a repository.
+// By default, a GoVanityResolver without fetcher is used, which only knows well-known vanity hosts;
+// use NewGoVanityResolver(&HTTPGoImportFetcher{}) to also follow the go-import meta tags of other hosts.
+func WithGoVanityResolver(r *GoVanityResolver) DepsDotDevOption {
+	return func(c *client) {
+		c.goVanity = r
+	}
 }
 
 // compile-time interface checks
 var _ IntrinsicTrustworthinessEvaluator = &client{}
 var _ DependencyResolver = &client{}
+var _ RepositoryResolver = &client{}
+var _ RepositoryScorer = &client{}
+
+// WithDepsDotDevLogger sets the logger used by the client; by default nothing is logged.
+func WithDepsDotDevLogger(logger *slog.Logger) DepsDotDevOption {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// WithDepsDotDevTracerProvider sets the OpenTelemetry tracer provider
+// used to create a span for each deps.dev RPC;
+// by default the global tracer provider is used.
+func WithDepsDotDevTracerProvider(tp trace.TracerProvider) DepsDotDevOption {
+	return func(c *client) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithDepsDotDevMetrics makes the client update the given Prometheus metrics.
+func WithDepsDotDevMetrics(m *Metrics) DepsDotDevOption {
+	return func(c *client) {
+		c.metrics = m
+	}
+}
+
+// WithDepsDotDevConverter sets the converter of OpenSSF scorecard scores to intrinsic trustworthiness;
+// by default a DefaultScoreTrustworthinessConverter is used (see also Parameters.Converter).
+func WithDepsDotDevConverter(converter ScoreTrustworthinessConverter) DepsDotDevOption {
+	return func(c *client) {
+		c.converter = converter
+	}
+}
+
+// WithRepositorySelector sets the strategy used when deps.dev associates
+// more than one source repository with a package version.
+// By default, a FirstRepositorySelector is used, as in previous versions;
+// a VerifiedRepositorySelector falling back to a LowestScoreRepositorySelector
+// guards against mislinked or hijacked repositories.
+func WithRepositorySelector(s RepositorySelector) DepsDotDevOption {
+	return func(c *client) {
+		c.repositorySelector = s
+	}
+}
+
+// WithDiscardedRepositoriesHandler sets a function that is called
+// once per package for which candidate repositories are discarded,
+// so that mislinked or hijacked repositories can be reported.
+func WithDiscardedRepositoriesHandler(f func(p Package, selection RepositorySelection)) DepsDotDevOption {
+	return func(c *client) {
+		c.onDiscard = f
+	}
+}
 
 // NewDepsDotDevClient creates an object that satisfies both the IntrinsicTrustworthinessEvaluator and DependencyResolver interfaces,
 // using the deps.dev API as the source of data.
@@ -27,7 +127,36 @@ var _ DependencyResolver = &client{}
 // Deprecated: in version 1 of package aggregdepscore,
 // the deps.dev client will be moved to a new Go module, most likely in a new repository,
 // and this function will be removed.
-func NewDepsDotDevClient() (*client, error) {
+func NewDepsDotDevClient(opts ...DepsDotDevOption) (*client, error) {
+	c := &client{
+		converter:          &DefaultScoreTrustworthinessConverter{},
+		goVanity:           NewGoVanityResolver(nil),
+		repositorySelector: &FirstRepositorySelector{},
+		projectCache:       make(map[string]*api.Project),
+		discardsReported:   make(map[Package]bool),
+		logger:             discardLogger,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.retry != nil {
+		err := c.retry.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+
+	if c.rateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must be positive, got %g", c.rateLimit)
+	}
+
+	if c.depsdotdev != nil {
+		c.depsdotdev = c.instrumented(c.depsdotdev)
+		return c, nil
+	}
+
 	connection, err := grpc.NewClient(
 		"api.deps.dev:443",
 		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
@@ -39,13 +168,35 @@ func NewDepsDotDevClient() (*client, error) {
 		return nil, fmt.Errorf("creating grpc connection: %w", err)
 	}
 
-	// TODO let the user ask for caching
-	// or provide its own cache
+	c.depsdotdev = c.instrumented(api.NewInsightsClient(connection))
 
-	return &client{
-		depsdotdev: api.NewInsightsClient(connection),
-		converter:  &DefaultScoreTrustworthinessConverter{},
-	}, nil
+	return c, nil
+}
+
+// instrumented wraps the deps.dev API client for tracing and metrics,
+// and for retries and rate limiting (each attempt is traced and counted)
+func (c *client) instrumented(insights api.InsightsClient) api.InsightsClient {
+	if c.metrics != nil {
+		insights = &meteredInsightsClient{InsightsClient: insights, metrics: c.metrics}
+	}
+
+	tracer := defaultTracer()
+	if c.tracerProvider != nil {
+		tracer = c.tracerProvider.Tracer(instrumentationName)
+	}
+
+	insights = &tracingInsightsClient{InsightsClient: insights, tracer: tracer}
+
+	if c.retry == nil && c.rateLimit == 0 {
+		return insights
+	}
+
+	retrying := &retryingInsightsClient{InsightsClient: insights, retry: c.retry}
+	if c.rateLimit > 0 {
+		retrying.limiter = newRateLimiter(c.rateLimit)
+	}
+
+	return retrying
 }
 
 func (c *client) getRespository(ctx context.Context, p Package) (string, error) {
@@ -65,66 +216,116 @@ func (c *client) getRespository(ctx context.Context, p Package) (string, error)
 		return "", fmt.Errorf("fetching package version: %w", err)
 	}
 
-	var repository string
+	candidates := RepositoryCandidates{Package: p}
 
-	for _, p := range version.RelatedProjects {
-		if p == nil {
+	for _, project := range version.RelatedProjects {
+		if project == nil {
 			continue
 		}
 
-		if p.RelationType != api.ProjectRelationType_SOURCE_REPO {
+		if project.RelationType != api.ProjectRelationType_SOURCE_REPO {
 			continue
 		}
 
-		if p.ProjectKey == nil {
+		if project.ProjectKey == nil {
 			continue
 		}
 
-		repository = p.ProjectKey.Id
+		candidates.Candidates = append(candidates.Candidates, RepositoryCandidate{
+			Repository: project.ProjectKey.Id,
+			Verified: project.RelationProvenance == api.ProjectRelationProvenance_SLSA_ATTESTATION ||
+				project.RelationProvenance == api.ProjectRelationProvenance_GO_ORIGIN,
+		})
+	}
+
+	for _, link := range version.Links {
+		if link == nil {
+			continue
+		}
 
-		// XXX assumes there is only one source repository
-		break
+		if link.Label == "SOURCE_REPO" || link.Label == "HOMEPAGE" {
+			candidates.DeclaredURLs = append(candidates.DeclaredURLs, link.Url)
+		}
 	}
 
-	if repository != "" {
-		return repository, nil
+	if len(candidates.Candidates) > 0 {
+		selection, err := c.repositorySelector.SelectRepository(ctx, candidates, c)
+		if err != nil {
+			return "", fmt.Errorf("selecting source repository: %w", err)
+		}
+
+		if len(selection.Discarded) > 0 && c.firstDiscard(p) {
+			for _, d := range selection.Discarded {
+				c.logger.WarnContext(ctx, "discarded candidate source repository",
+					slog.Any("package", p),
+					slog.String("selected_repository", selection.Repository),
+					slog.String("discarded_repository", d.Repository),
+					slog.String("reason", d.Reason),
+				)
+			}
+
+			if c.onDiscard != nil {
+				c.onDiscard(p, selection)
+			}
+		}
+
+		return selection.Repository, nil
 	}
 
 	// deps.dev does not find the repository for gopkg.in packages
 	// Cédric Van Rompay reported it to depsdev@google.com on 2025-01-03
-	// in the meantime we use this workaround
-	if p.Ecosystem == "go" && strings.HasPrefix(p.Name, "gopkg.in/") {
-		repository, err := getGopkginRepository(p.Name)
+	// and it is also the case for many other vanity import paths
+	// (golang.org/x/*, k8s.io/*, go.uber.org/*...)
+	// so we resolve them ourselves
+	if p.Ecosystem == "go" && c.goVanity != nil {
+		repository, err := c.goVanity.ResolveRepository(ctx, p.Name)
 		if err != nil {
-			return "", fmt.Errorf("getting repository for gopkg.in package: %w", err)
+			return "", fmt.Errorf("resolving repository of Go vanity import path: %w", err)
 		}
 
+		c.logger.DebugContext(ctx, "resolved source repository of Go vanity import path",
+			slog.Any("package", p),
+			slog.String("repository", repository),
+		)
+
 		return repository, nil
 	}
 
 	return "", fmt.Errorf("no source repository found for package version")
 }
 
+// firstDiscard tells if discarded repositories of p were not reported yet,
+// and marks them as reported
+func (c *client) firstDiscard(p Package) bool {
+	c.discardsReportedMutex.Lock()
+	defer c.discardsReportedMutex.Unlock()
+
+	if c.discardsReported[p] {
+		return false
+	}
+	c.discardsReported[p] = true
+
+	return true
+}
+
+// GetRepository returns the source repository of the package as known by deps.dev,
+// for instance "github.com/psf/requests".
+func (c *client) GetRepository(ctx context.Context, p Package) (string, error) {
+	return c.getRespository(ctx, p)
+}
+
 func (c *client) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
 	repository, err := c.getRespository(ctx, p)
 	if err != nil {
 		return 0, fmt.Errorf("getting repository: %w", err)
 	}
 
-	project, err := c.depsdotdev.GetProject(ctx, &api.GetProjectRequest{
-		ProjectKey: &api.ProjectKey{
-			Id: repository,
-		},
-	})
+	scorecardScore, err := c.GetRepositoryScore(ctx, repository)
 	if err != nil {
-		return 0, fmt.Errorf("fetching project (%s): %w", repository, err)
-	}
-
-	if project.Scorecard == nil {
-		return 0, fmt.Errorf("no scorecard found for project (%s)", repository)
+		return 0, err
 	}
 
-	score := float64(project.Scorecard.OverallScore) / 10.0
+	score := scorecardScore / 10.0
 
 	// XXX OSSF scorecard tends to give pretty low scores
 	// so we may want to adjust the trustworthiness
@@ -135,6 +336,51 @@ func (c *client) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package
 
 }
 
+// GetRepositoryScore returns the OSSF scorecard overall score of the repository
+// (between 0 and 10) as known by deps.dev.
+func (c *client) GetRepositoryScore(ctx context.Context, repository string) (float64, error) {
+	project, err := c.getProject(ctx, repository)
+	if err != nil {
+		return 0, fmt.Errorf("fetching project (%s): %w", repository, err)
+	}
+
+	if project.Scorecard == nil {
+		return 0, fmt.Errorf("no scorecard found for project (%s)", repository)
+	}
+
+	return float64(project.Scorecard.OverallScore), nil
+}
+
+func (c *client) getProject(ctx context.Context, repository string) (*api.Project, error) {
+	c.projectCacheMutex.Lock()
+	project, ok := c.projectCache[repository]
+	c.projectCacheMutex.Unlock()
+
+	if c.metrics != nil {
+		c.metrics.cacheLookup("depsdotdev_project", ok)
+	}
+
+	if ok {
+		c.logger.DebugContext(ctx, "deps.dev project cache hit", slog.String("repository", repository))
+		return project, nil
+	}
+
+	project, err := c.depsdotdev.GetProject(ctx, &api.GetProjectRequest{
+		ProjectKey: &api.ProjectKey{
+			Id: repository,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.projectCacheMutex.Lock()
+	c.projectCache[repository] = project
+	c.projectCacheMutex.Unlock()
+
+	return project, nil
+}
+
 func (c *client) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
 	ecosystem, err := depsdotdevEcosystem(p.Ecosystem)
 	if err != nil {
@@ -187,9 +433,11 @@ func (c *client) GetDirectDependencies(ctx context.Context, p Package) ([]Packag
 			}
 
 			result = append(result, bundledDependencies...)
+		} else {
+			c.logger.WarnContext(ctx, "ignoring bundled dependencies: only supported for npm",
+				slog.Any("package", p),
+			)
 		}
-
-		// TODO (https://github.com/DataDog/aggregated-dependency-score/issues/19) log a warning if hasBundledDependencies is true and the ecosystem is NOT npm
 	}
 
 	return result, nil
@@ -219,7 +467,9 @@ func (c *client) getNPMBundledDependencies(ctx context.Context, versionKey *api.
 
 	for _, dep := range requirements.Npm.Bundled {
 		if dep == nil {
-			// TODO (https://github.com/DataDog/aggregated-dependency-score/issues/19) log a warning
+			c.logger.WarnContext(ctx, "ignoring nil bundled dependency",
+				slog.String("version_key", versionKey.String()),
+			)
 			continue
 		}
 
@@ -232,7 +482,11 @@ func (c *client) getNPMBundledDependencies(ctx context.Context, versionKey *api.
 		// so we use it instead.
 
 		if !strings.HasPrefix(dep.Path, "node_modules/") {
-			// TODO (https://github.com/DataDog/aggregated-dependency-score/issues/19) log a warning
+			c.logger.WarnContext(ctx, "ignoring bundled dependency with unexpected path",
+				slog.String("version_key", versionKey.String()),
+				slog.String("path", dep.Path),
+				slog.String("name", dep.Name),
+			)
 			continue
 		}
 
@@ -0,0 +1,70 @@
+package aggregdepscore
+
+import (
+	"context"
+	"testing"
+
+	api "deps.dev/api/v3"
+
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+func TestDepsDotDevProjectCache(t *testing.T) {
+	fake := depsdotdevfake.New()
+
+	monorepo := "github.com/babel/babel"
+	fake.AddProject(monorepo, 7.5)
+
+	var names []string
+	for _, name := range []string{"@babel/core", "@babel/parser", "@babel/types"} {
+		fake.AddVersion(api.System_NPM, name, "7.0.0", []string{monorepo})
+		names = append(names, name)
+	}
+
+	c, err := NewDepsDotDevClient(WithInsightsClient(fake))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range names {
+		_, err := c.EvaluateIntrinsicTrustworthiness(context.Background(), Package{Ecosystem: "npm", Name: name, Version: "7.0.0"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if fake.Calls("GetProject") != 1 {
+		t.Fatalf("expected 1 call to GetProject, got %d", fake.Calls("GetProject"))
+	}
+}
+
+func TestDepsDotDevReportsDiscardsOnce(t *testing.T) {
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/expressjs/express", 8)
+	fake.AddProject("github.com/mislinked/express", 3)
+	fake.AddVersion(api.System_NPM, "express", "4.18.2", []string{"github.com/expressjs/express", "github.com/mislinked/express"})
+
+	nbReports := 0
+	c, err := NewDepsDotDevClient(
+		WithInsightsClient(fake),
+		WithDiscardedRepositoriesHandler(func(p Package, selection RepositorySelection) {
+			nbReports++
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the repository is looked up for the intrinsic trustworthiness, overrides and trust domains
+	p := Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"}
+	for i := 0; i < 3; i++ {
+		_, err := c.GetRepository(context.Background(), p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if nbReports != 1 {
+		t.Errorf("expected discarded repositories to be reported once, got %d reports", nbReports)
+	}
+}
@@ -0,0 +1,213 @@
+package aggregdepscore
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// EdgeChange is the contribution of an edge of the dependency graph
+// to the change of the aggregated trustworthiness of the root between two evaluations
+// (see DiffEvaluations).
+type EdgeChange struct {
+	// Parent is the package the edge starts from
+	// (as in the newer evaluation when it is in both)
+	Parent Package
+	// Before is the dependency in the older evaluation, nil if the edge was added
+	Before *Package
+	// After is the dependency in the newer evaluation, nil if the edge was removed
+	After *Package
+	// Depth is the lowest depth of the dependency among the paths going through the edge
+	Depth int
+	// NbPaths is the number of paths from the root going through the edge
+	NbPaths int
+	// LogContribution is the contribution of the edge to the change of
+	// the log of the aggregated trustworthiness of the root, summed over the paths going through the edge;
+	// exp(LogContribution) is the factor the edge multiplies the aggregated trustworthiness by.
+	//
+	// For an added or removed edge, it covers the dependency and all its own dependencies.
+	// For a dependency that is in both evaluations, it only covers the change of the dependency itself
+	// (mostly its intrinsic trustworthiness): changes of its own dependencies are other edges.
+	LogContribution float64
+}
+
+// Added tells if the edge is only in the newer evaluation.
+func (c *EdgeChange) Added() bool {
+	return c.Before == nil
+}
+
+// Removed tells if the edge is only in the older evaluation.
+func (c *EdgeChange) Removed() bool {
+	return c.After == nil
+}
+
+// DiffEvaluations attributes the change of the aggregated trustworthiness of the root
+// between two evaluations to the edges of the dependency graph,
+// the edges that lowered the aggregated trustworthiness the most first.
+// Edges that did not change the aggregated trustworthiness are not returned,
+// unless the version of the dependency changed.
+//
+// Both evaluations must have a tree (see WithEvaluationTree).
+// Dependencies are matched by ecosystem and name, so that upgrades are edges that changed.
+// The contributions of all edges, plus the change of the root package itself, sum to
+// the change of Evaluation.LogTrustworthiness.
+func DiffEvaluations(before *Evaluation, after *Evaluation) ([]EdgeChange, error) {
+	if before.Tree == nil || after.Tree == nil {
+		return nil, errors.New("diffing evaluations requires evaluation trees, see WithEvaluationTree")
+	}
+
+	if before.Tree.transitiveExponent() != after.Tree.transitiveExponent() {
+		return nil, errors.New("diffing evaluations requires the same transitive trustworthiness exponent, see WithParameters")
+	}
+
+	d := &evaluationDiff{changes: make(map[edgeKey]*EdgeChange)}
+	d.diffChildren(after.Tree.Package, before.Tree, after.Tree, 1)
+
+	var result []EdgeChange
+	for _, change := range d.changes {
+		if change.LogContribution == 0 && change.Before != nil && change.After != nil && *change.Before == *change.After {
+			continue
+		}
+		result = append(result, *change)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.LogContribution != b.LogContribution {
+			return a.LogContribution < b.LogContribution
+		}
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		return edgeKeyOf(a.Parent, a.Before, a.After).less(edgeKeyOf(b.Parent, b.Before, b.After))
+	})
+
+	return result, nil
+}
+
+type edgeKey struct {
+	parent Package
+	before Package
+	after  Package
+}
+
+func edgeKeyOf(parent Package, before *Package, after *Package) edgeKey {
+	key := edgeKey{parent: parent}
+	if before != nil {
+		key.before = *before
+	}
+	if after != nil {
+		key.after = *after
+	}
+
+	return key
+}
+
+func (k edgeKey) less(other edgeKey) bool {
+	for _, pair := range [][2]Package{{k.parent, other.parent}, {k.after, other.after}, {k.before, other.before}} {
+		if pair[0] != pair[1] {
+			return pair[0].String() < pair[1].String()
+		}
+	}
+
+	return false
+}
+
+type evaluationDiff struct {
+	changes map[edgeKey]*EdgeChange
+}
+
+// diffChildren matches the children of two nodes for the same package
+// and records the contributions of the edges to them;
+// weight is the factor of the log of the aggregated trustworthiness of a child
+// in the log of the aggregated trustworthiness of the root, that is e^depth.
+func (d *evaluationDiff) diffChildren(parent Package, before *Node, after *Node, weight float64) {
+	weight *= after.transitiveExponent()
+
+	unmatched := make(map[string][]*Node)
+	for _, child := range before.Children {
+		key := matchingKey(child.Package)
+		unmatched[key] = append(unmatched[key], child)
+	}
+
+	for _, child := range after.Children {
+		key := matchingKey(child.Package)
+		candidates := unmatched[key]
+		if len(candidates) == 0 {
+			d.record(parent, nil, child, weight*child.LogAggregatedTrustworthiness)
+			continue
+		}
+
+		previous := candidates[0]
+		unmatched[key] = candidates[1:]
+
+		d.record(parent, previous, child, weight*logDifference(ownLogTrustworthiness(previous), ownLogTrustworthiness(child)))
+		d.diffChildren(child.Package, previous, child, weight)
+	}
+
+	// removed edges, in the order of the older tree
+	for _, child := range before.Children {
+		for _, candidate := range unmatched[matchingKey(child.Package)] {
+			if candidate == child {
+				d.record(parent, child, nil, -weight*child.LogAggregatedTrustworthiness)
+			}
+		}
+	}
+}
+
+func (d *evaluationDiff) record(parent Package, before *Node, after *Node, logContribution float64) {
+	var beforePackage, afterPackage *Package
+	depth := 0
+	if before != nil {
+		beforePackage = &before.Package
+		depth = before.Depth
+	}
+	if after != nil {
+		afterPackage = &after.Package
+		depth = after.Depth
+	}
+
+	key := edgeKeyOf(parent, beforePackage, afterPackage)
+	change, ok := d.changes[key]
+	if !ok {
+		change = &EdgeChange{Parent: parent, Before: beforePackage, After: afterPackage, Depth: depth}
+		d.changes[key] = change
+	}
+
+	change.NbPaths++
+	change.Depth = min(change.Depth, depth)
+	if logContribution != 0 {
+		change.LogContribution += logContribution
+	}
+}
+
+// matchingKey identifies a dependency across versions
+func matchingKey(p Package) string {
+	return p.Ecosystem + "\x00" + p.Name
+}
+
+// ownLogTrustworthiness is the part of the log of the aggregated trustworthiness of node
+// that does not come from its children: the log of its intrinsic trustworthiness,
+// adjusted for truncations and trust domain grouping
+func ownLogTrustworthiness(node *Node) float64 {
+	var children float64
+	for _, child := range node.Children {
+		children += node.transitiveExponent() * child.LogAggregatedTrustworthiness
+	}
+
+	if math.IsInf(children, -1) {
+		// a dependency has a zero trustworthiness, it takes the blame
+		return 0
+	}
+
+	return node.LogAggregatedTrustworthiness - children
+}
+
+// logDifference is after - before, with no change between infinite values
+func logDifference(before float64, after float64) float64 {
+	if before == after {
+		return 0
+	}
+
+	return after - before
+}
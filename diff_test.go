@@ -0,0 +1,102 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDiffEvaluations(t *testing.T) {
+	evaluate := func(trustworthinessByName map[string]float64, directDependencyNamesByName map[string][]string, deps []string) *Evaluation {
+		evaluator, err := NewEvaluator(
+			&testIntrinsicTrustworthinessEvaluator{trustworthinessByName: trustworthinessByName},
+			&testDependencyResolver{directDependencyNamesByName: directDependencyNamesByName},
+			WithEvaluationTree(),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var packages []Package
+		for _, name := range deps {
+			packages = append(packages, Package{Name: name})
+		}
+
+		evaluation, err := evaluator.EvaluateProject(context.Background(), "my-app", packages)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return evaluation
+	}
+
+	before := evaluate(
+		map[string]float64{"A": 0.9, "B": 0.95, "C": 0.8},
+		map[string][]string{"A": {"C"}, "B": {"C"}, "C": {}},
+		[]string{"A", "B"},
+	)
+	after := evaluate(
+		map[string]float64{"A": 0.85, "C": 0.8, "D": 0.7, "E": 0.99},
+		map[string][]string{"A": {"C", "E"}, "C": {}, "D": {}, "E": {}},
+		[]string{"A", "D"},
+	)
+
+	changes, err := DiffEvaluations(before, after)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	describe := func(p *Package) string {
+		if p == nil {
+			return "-"
+		}
+		return p.Name
+	}
+
+	var actual []string
+	total := 0.0
+	for _, change := range changes {
+		actual = append(actual, fmt.Sprintf("%s: %s -> %s (depth %d, %d paths)", change.Parent.Name, describe(change.Before), describe(change.After), change.Depth, change.NbPaths))
+		total += change.LogContribution
+	}
+
+	// the edge to D lowers the trustworthiness the most, the removal of B raises it
+	expected := []string{
+		"my-app: - -> D (depth 1, 1 paths)",
+		"my-app: A -> A (depth 1, 1 paths)",
+		"A: - -> E (depth 2, 1 paths)",
+		"my-app: B -> - (depth 1, 1 paths)",
+	}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	expectedContributions := map[string]float64{
+		"D": 1.5 * math.Log(0.7),
+		"A": 1.5 * math.Log(0.85/0.9),
+		"E": 1.5 * 1.5 * math.Log(0.99),
+		"B": -1.5 * (math.Log(0.95) + 1.5*math.Log(0.8)),
+	}
+	for _, change := range changes {
+		name := describe(change.After)
+		if change.Removed() {
+			name = describe(change.Before)
+		}
+		if math.Abs(change.LogContribution-expectedContributions[name]) > 1e-12 {
+			t.Errorf("%s: expected a contribution of %f, got %f", name, expectedContributions[name], change.LogContribution)
+		}
+	}
+
+	delta := after.LogTrustworthiness - before.LogTrustworthiness
+	if math.Abs(total-delta) > 1e-12 {
+		t.Errorf("expected the contributions to sum to %f, got %f", delta, total)
+	}
+}
+
+func TestDiffEvaluationsWithoutTree(t *testing.T) {
+	_, err := DiffEvaluations(&Evaluation{}, &Evaluation{})
+	if err == nil {
+		t.Errorf("expected an error without evaluation trees")
+	}
+}
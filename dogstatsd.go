@@ -0,0 +1,199 @@
+package aggregdepscore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GaugeEmitter sends gauge metrics to a monitoring system.
+// Implementations may buffer metrics until Flush is called.
+type GaugeEmitter interface {
+	Gauge(name string, value float64, tags []string) error
+	Flush() error
+}
+
+const (
+	// maximum payload sizes recommended by the Datadog documentation
+	// (see https://docs.datadoghq.com/developers/dogstatsd/high_throughput/)
+	dogstatsdMaxUDPPayload = 1432
+	dogstatsdMaxUDSPayload = 8192
+)
+
+// DogStatsDEmitter is a GaugeEmitter sending metrics
+// to a Datadog agent using the DogStatsD protocol, over UDP or a Unix domain socket.
+//
+// Metrics are batched: several metrics are sent in a single packet,
+// separated by newlines, as long as the packet stays under the maximum payload size.
+type DogStatsDEmitter struct {
+	mutex      sync.Mutex
+	connection net.Conn
+	maxPayload int
+	buffer     []byte
+}
+
+// compile-time interface checks
+var _ GaugeEmitter = &DogStatsDEmitter{}
+
+// NewDogStatsDEmitter connects to a DogStatsD server.
+// Address is either "host:port" or "udp://host:port" for UDP,
+// or "unix:///path/to/dsd.socket" for a Unix domain socket (datagram mode).
+func NewDogStatsDEmitter(address string) (*DogStatsDEmitter, error) {
+	network := "udp"
+	maxPayload := dogstatsdMaxUDPPayload
+
+	if rest, ok := strings.CutPrefix(address, "unix://"); ok {
+		network = "unixgram"
+		maxPayload = dogstatsdMaxUDSPayload
+		address = rest
+	} else {
+		address = strings.TrimPrefix(address, "udp://")
+	}
+
+	connection, err := net.Dial(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to DogStatsD server: %w", err)
+	}
+
+	return &DogStatsDEmitter{
+		connection: connection,
+		maxPayload: maxPayload,
+	}, nil
+}
+
+func (e *DogStatsDEmitter) Gauge(name string, value float64, tags []string) error {
+	line := formatDogStatsDGauge(name, value, tags)
+
+	if len(line) > e.maxPayload {
+		return fmt.Errorf("metric %q is larger than the maximum payload size (%d bytes)", name, e.maxPayload)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// +1 for the newline separating metrics
+	if len(e.buffer) > 0 && len(e.buffer)+1+len(line) > e.maxPayload {
+		err := e.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(e.buffer) > 0 {
+		e.buffer = append(e.buffer, '\n')
+	}
+	e.buffer = append(e.buffer, line...)
+
+	return nil
+}
+
+// Flush sends buffered metrics.
+func (e *DogStatsDEmitter) Flush() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.flush()
+}
+
+// flush must be called with the mutex held
+func (e *DogStatsDEmitter) flush() error {
+	if len(e.buffer) == 0 {
+		return nil
+	}
+
+	_, err := e.connection.Write(e.buffer)
+	e.buffer = e.buffer[:0]
+	if err != nil {
+		return fmt.Errorf("sending metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes buffered metrics and closes the connection.
+func (e *DogStatsDEmitter) Close() error {
+	return errors.Join(e.Flush(), e.connection.Close())
+}
+
+func formatDogStatsDGauge(name string, value float64, tags []string) string {
+	var b strings.Builder
+
+	b.WriteString(sanitizeDogStatsD(name))
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	b.WriteString("|g")
+
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		for i, tag := range tags {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(sanitizeDogStatsD(tag))
+		}
+	}
+
+	return b.String()
+}
+
+var dogstatsdReplacer = strings.NewReplacer("|", "_", ",", "_", "\n", "_", "#", "_", "@", "_")
+
+// sanitizeDogStatsD replaces characters that have a meaning in the DogStatsD protocol
+func sanitizeDogStatsD(s string) string {
+	return dogstatsdReplacer.Replace(s)
+}
+
+// scoreEmittingObserver emits the intrinsic and aggregated score of every evaluated package
+type scoreEmittingObserver struct {
+	NopObserver
+	emitter   GaugeEmitter
+	converter ScoreTrustworthinessConverter
+	// emission errors are logged, they do not fail evaluations
+	logger *slog.Logger
+}
+
+// scoreMetricName is the name of the gauge emitted for each evaluated package
+const scoreMetricName = "aggregdepscore.score"
+
+func (o *scoreEmittingObserver) NodeFinished(ctx context.Context, event NodeFinishedEvent) {
+	tags := []string{
+		"ecosystem:" + event.Package.Ecosystem,
+		"package:" + event.Package.Name,
+		"version:" + event.Package.Version,
+	}
+
+	if root, ok := rootPackageFromContext(ctx); ok {
+		tags = append(tags, "project:"+root.Name)
+	}
+
+	for _, each := range []struct {
+		kind            string
+		trustworthiness float64
+	}{
+		{"intrinsic", event.IntrinsicTrustworthiness},
+		{"aggregated", event.AggregatedTrustworthiness},
+	} {
+		kindTags := append(tags[:len(tags):len(tags)], "kind:"+each.kind)
+
+		err := o.emitter.Gauge(scoreMetricName, o.converter.ScoreFromTrustworthiness(each.trustworthiness), kindTags)
+		if err != nil {
+			o.logger.WarnContext(ctx, "could not emit score", slog.Any("package", event.Package), slog.String("error", err.Error()))
+		}
+	}
+}
+
+type rootPackageContextKey struct{}
+
+func withRootPackage(ctx context.Context, p Package) context.Context {
+	return context.WithValue(ctx, rootPackageContextKey{}, p)
+}
+
+func rootPackageFromContext(ctx context.Context) (Package, bool) {
+	p, ok := ctx.Value(rootPackageContextKey{}).(Package)
+	return p, ok
+}
@@ -0,0 +1,158 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenDogStatsD starts a local UDP listener and returns its address
+// and a function returning the packets received so far
+func listenDogStatsD(t *testing.T) (string, func() []string) {
+	t.Helper()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	packets := make(chan string, 1000)
+	go func() {
+		buffer := make([]byte, 65536)
+		for {
+			n, _, err := listener.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			packets <- string(buffer[:n])
+		}
+	}()
+
+	received := func() []string {
+		var result []string
+		for {
+			select {
+			case p := <-packets:
+				result = append(result, p)
+			case <-time.After(100 * time.Millisecond):
+				return result
+			}
+		}
+	}
+
+	return listener.LocalAddr().String(), received
+}
+
+func TestDogStatsDBatching(t *testing.T) {
+	address, received := listenDogStatsD(t)
+
+	emitter, err := NewDogStatsDEmitter("udp://" + address)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer emitter.Close()
+
+	nbMetrics := 100
+	for i := 0; i < nbMetrics; i++ {
+		err := emitter.Gauge("test.metric", float64(i), []string{fmt.Sprintf("package:package-%d", i), "ecosystem:npm"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	err = emitter.Flush()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packets := received()
+
+	if len(packets) < 2 {
+		t.Fatalf("expected metrics to be split in several packets, got %d", len(packets))
+	}
+
+	var lines []string
+	for _, packet := range packets {
+		if len(packet) > dogstatsdMaxUDPPayload {
+			t.Errorf("packet of %d bytes exceeds the maximum payload size", len(packet))
+		}
+
+		if strings.HasPrefix(packet, "\n") || strings.HasSuffix(packet, "\n") {
+			t.Errorf("packet has a leading or trailing newline: %q", packet)
+		}
+
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+
+	if len(lines) != nbMetrics {
+		t.Fatalf("expected %d metrics, got %d", nbMetrics, len(lines))
+	}
+
+	for i, line := range lines {
+		expected := fmt.Sprintf("test.metric:%d|g|#package:package-%d,ecosystem:npm", i, i)
+		if line != expected {
+			t.Errorf("expected %q, got %q", expected, line)
+		}
+	}
+}
+
+func TestFormatDogStatsDGaugeSanitizesTags(t *testing.T) {
+	actual := formatDogStatsDGauge("aggregdepscore.score", 0.5, []string{"package:a|b,c#d"})
+	expected := "aggregdepscore.score:0.5|g|#package:a_b_c_d"
+
+	if actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestScoreEmission(t *testing.T) {
+	address, received := listenDogStatsD(t)
+
+	emitter, err := NewDogStatsDEmitter(address)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer emitter.Close()
+
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.92, "B": 0.94},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"B"}, "B": {}},
+		},
+		WithScoreEmitter(emitter),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.Evaluate(context.Background(), Package{Ecosystem: "npm", Name: "A", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packets := received()
+	if len(packets) != 1 {
+		t.Fatalf("expected a single packet, got %q", packets)
+	}
+
+	lines := strings.Split(packets[0], "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 metrics (intrinsic and aggregated for 2 packages), got %q", lines)
+	}
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, scoreMetricName+":") || !strings.Contains(line, "project:A") {
+			t.Errorf("unexpected metric %q", line)
+		}
+	}
+
+	if !strings.Contains(lines[3], "ecosystem:npm,package:A,version:1.0.0,project:A,kind:aggregated") {
+		t.Errorf("unexpected tags for the root package: %q", lines[3])
+	}
+}
@@ -0,0 +1,114 @@
+package aggregdepscore
+
+import "math"
+
+// Evaluation is the detailed result of Evaluator.Evaluate.
+type Evaluation struct {
+	Package Package
+	Score   float64
+	// Trustworthiness is the aggregated trustworthiness of the package,
+	// noted T' in the design paper
+	Trustworthiness float64
+	// LogTrustworthiness is the natural logarithm of Trustworthiness;
+	// unlike Trustworthiness, it does not underflow to zero for huge dependency graphs
+	LogTrustworthiness float64
+	// RankingScore is equal to Score when Score is positive,
+	// but unlike Score it is not clamped at zero:
+	// below the minimum trustworthiness, it decreases by one
+	// each time Trustworthiness is multiplied by the minimum trustworthiness (0.8).
+	// It can be used to rank packages that all have a score of zero.
+	RankingScore float64
+	// Components details the aggregated trustworthiness of the package
+	Components TrustworthinessComponents
+	// DirectDependencies are the direct dependencies of the package
+	// that were evaluated (dependencies closing a cycle are not included)
+	DirectDependencies []DirectDependency
+	// Tree is the tree of evaluated packages, nil unless WithEvaluationTree is used
+	Tree *Node
+	// BrokenCycles are the dependency cycles that were ignored during the evaluation
+	BrokenCycles []Cycle
+	// Fallbacks lists the packages for which an intrinsic trustworthiness was assumed
+	// (see WithIntrinsicFallback)
+	Fallbacks []Fallback
+	// NbNodes is the number of package evaluations;
+	// a package reachable through several paths is counted once per path
+	NbNodes int
+	// Truncated tells if some subtrees were not fully evaluated
+	// because of the limits set with WithLimits
+	Truncated   bool
+	Truncations []Truncation
+}
+
+// TrustworthinessComponents is the aggregated trustworthiness of a package
+// and the components it is computed from:
+// Aggregated = Intrinsic × Transitive.
+//
+// Log values are natural logarithms;
+// unlike linear values, they do not underflow to zero for huge dependency graphs.
+type TrustworthinessComponents struct {
+	// Aggregated is the aggregated trustworthiness of the package, noted T' in the design paper
+	Aggregated float64
+	// Intrinsic is the intrinsic trustworthiness of the package, noted t in the design paper
+	Intrinsic float64
+	// Transitive is the product over direct dependencies q of T'(q)^e
+	// (or over trust domains, see WithTrustDomainGrouping);
+	// it is 1 for packages without dependencies
+	Transitive float64
+
+	LogAggregated float64
+	LogIntrinsic  float64
+	LogTransitive float64
+}
+
+func newTrustworthinessComponents(logIntrinsic float64, logTransitive float64) TrustworthinessComponents {
+	logAggregated := logIntrinsic + logTransitive
+
+	return TrustworthinessComponents{
+		Aggregated:    math.Exp(logAggregated),
+		Intrinsic:     math.Exp(logIntrinsic),
+		Transitive:    math.Exp(logTransitive),
+		LogAggregated: logAggregated,
+		LogIntrinsic:  logIntrinsic,
+		LogTransitive: logTransitive,
+	}
+}
+
+// DirectDependency is the result of the evaluation of a direct dependency
+// of the package being evaluated.
+type DirectDependency struct {
+	Package                      Package
+	Score                        float64
+	AggregatedTrustworthiness    float64
+	LogAggregatedTrustworthiness float64
+}
+
+func (e *Evaluator) directDependencies(evaluated []evaluatedDependency) []DirectDependency {
+	var result []DirectDependency
+	for _, dep := range evaluated {
+		aggregated := math.Exp(dep.logAggregated)
+		result = append(result, DirectDependency{
+			Package:                      dep.pkg,
+			Score:                        e.converter.ScoreFromTrustworthiness(aggregated),
+			AggregatedTrustworthiness:    aggregated,
+			LogAggregatedTrustworthiness: dep.logAggregated,
+		})
+	}
+
+	return result
+}
+
+// Cycle is a dependency cycle:
+// the first package of Packages depends (transitively) on itself,
+// as the same package appears again as the last element.
+type Cycle struct {
+	Packages []Package
+}
+
+// rankingScore extends score below the minimum trustworthiness (see Evaluation.RankingScore)
+func rankingScore(score float64, logTrustworthiness float64, minTrustworthiness float64) float64 {
+	if score > 0 {
+		return score
+	}
+
+	return 1 - logTrustworthiness/math.Log(minTrustworthiness)
+}
@@ -13,6 +13,7 @@ func getGopkginRepository(packageName string) (string, error) {
 	// note: a more "correct" way would be to send a request to gopkg.in
 	// and look for a "<meta name='go-import'>" tag in the response
 	// (see https://pkg.go.dev/cmd/go#hdr-Remote_import_paths)
+	// (which is what GoVanityResolver does for other vanity import paths)
 	// but using a regexp is simpler and saves us a network request
 
 	// we follow the logic in https://labix.org/gopkg.in
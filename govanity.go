@@ -0,0 +1,385 @@
+package aggregdepscore
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoImportFetcher fetches the HTML page that a Go vanity import path serves
+// to the go command, that is https://<importPath>?go-get=1
+// (see https://pkg.go.dev/cmd/go#hdr-Remote_import_paths).
+type GoImportFetcher interface {
+	FetchGoImportPage(ctx context.Context, importPath string) ([]byte, error)
+}
+
+// HTTPGoImportFetcher is a GoImportFetcher sending HTTPS requests.
+type HTTPGoImportFetcher struct {
+	// Client is the HTTP client to use;
+	// if nil, a client with a timeout of 10 seconds is used
+	// so that a slow vanity host cannot stall evaluations
+	Client *http.Client
+}
+
+var defaultGoImportClient = &http.Client{Timeout: 10 * time.Second}
+
+// compile-time interface checks
+var _ GoImportFetcher = &HTTPGoImportFetcher{}
+
+// maxGoImportPageSize limits how much we read from vanity servers;
+// meta tags are expected in the <head> so there is no need to read more
+const maxGoImportPageSize = 1 << 20
+
+func (f *HTTPGoImportFetcher) FetchGoImportPage(ctx context.Context, importPath string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = defaultGoImportClient
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+importPath+"?go-get=1", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxGoImportPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return body, nil
+}
+
+// GoVanityResolver finds the source repository of Go modules
+// whose import path is not the one of their repository,
+// such as golang.org/x/net or go.uber.org/zap.
+//
+// Well-known vanity hosts are resolved using a built-in table;
+// if the resolver has a GoImportFetcher, other import paths are resolved by following
+// the "go-import" and "go-source" meta tags served by the vanity host.
+type GoVanityResolver struct {
+	fetcher GoImportFetcher
+
+	cacheMutex sync.Mutex
+	// cache maps import path prefixes (as declared in go-import meta tags)
+	// to repositories
+	cache map[string]string
+}
+
+// NewGoVanityResolver creates a GoVanityResolver using fetcher to retrieve go-import meta tags,
+// for instance an HTTPGoImportFetcher;
+// if fetcher is nil, only the built-in table is used and no network request is sent.
+func NewGoVanityResolver(fetcher GoImportFetcher) *GoVanityResolver {
+	return &GoVanityResolver{
+		fetcher: fetcher,
+		cache:   make(map[string]string),
+	}
+}
+
+// hosts for which the repository can be read directly from the import path;
+// these are also the hosts deps.dev knows about
+var goRepositoryHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// wellKnownGoVanityPrefixes are vanity hosts where the first path element
+// after the prefix is the name of the repository
+var wellKnownGoVanityPrefixes = []struct {
+	prefix           string
+	repositoryPrefix string
+}{
+	{"golang.org/x/", "github.com/golang/"},
+	{"k8s.io/", "github.com/kubernetes/"},
+	{"sigs.k8s.io/", "github.com/kubernetes-sigs/"},
+	{"go.uber.org/", "github.com/uber-go/"},
+	{"go.etcd.io/", "github.com/etcd-io/"},
+}
+
+// wellKnownGoVanityRoots are vanity import paths that do not follow a simple pattern
+var wellKnownGoVanityRoots = map[string]string{
+	"google.golang.org/grpc":      "github.com/grpc/grpc-go",
+	"google.golang.org/protobuf":  "github.com/protocolbuffers/protobuf-go",
+	"google.golang.org/genproto":  "github.com/googleapis/go-genproto",
+	"google.golang.org/api":       "github.com/googleapis/google-api-go-client",
+	"google.golang.org/appengine": "github.com/golang/appengine",
+	"cloud.google.com/go":         "github.com/googleapis/google-cloud-go",
+	"go.opentelemetry.io/otel":    "github.com/open-telemetry/opentelemetry-go",
+	"go.opentelemetry.io/contrib": "github.com/open-telemetry/opentelemetry-go-contrib",
+	"go.mongodb.org/mongo-driver": "github.com/mongodb/mongo-go-driver",
+	"honnef.co/go/tools":          "github.com/dominikh/go-tools",
+	"deps.dev/api/v3":             "github.com/google/deps.dev",
+	"filippo.io/edwards25519":     "github.com/FiloSottile/edwards25519",
+	"dario.cat/mergo":             "github.com/darccio/mergo",
+}
+
+// builtinGoRepository resolves import paths without network requests;
+// it returns an empty string if the import path is not known.
+func builtinGoRepository(importPath string) string {
+	elements := strings.Split(importPath, "/")
+
+	for _, host := range goRepositoryHosts {
+		if elements[0] == host && len(elements) >= 3 {
+			return strings.Join(elements[:3], "/")
+		}
+	}
+
+	if elements[0] == "gopkg.in" {
+		repository, err := getGopkginRepository(goPathWithoutSubpackage(importPath))
+		if err == nil {
+			return repository
+		}
+	}
+
+	for _, each := range wellKnownGoVanityPrefixes {
+		rest, ok := strings.CutPrefix(importPath, each.prefix)
+		if !ok || rest == "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(rest, "/")
+		return each.repositoryPrefix + name
+	}
+
+	// longest match wins
+	var repository string
+	longestRoot := 0
+	for root, r := range wellKnownGoVanityRoots {
+		if hasPathPrefix(importPath, root) && len(root) > longestRoot {
+			repository = r
+			longestRoot = len(root)
+		}
+	}
+
+	return repository
+}
+
+// goPathWithoutSubpackage strips the path of a package inside a gopkg.in module,
+// e.g. "gopkg.in/yaml.v3/internal" becomes "gopkg.in/yaml.v3"
+func goPathWithoutSubpackage(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	for i := range elements {
+		modulePath := strings.Join(elements[:i+1], "/")
+		if gopkginRegexp.MatchString(modulePath) {
+			return modulePath
+		}
+	}
+
+	return importPath
+}
+
+func hasPathPrefix(importPath, prefix string) bool {
+	return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
+}
+
+// ResolveRepository returns the source repository of the Go module or package
+// with the given import path, for instance "github.com/golang/net" for "golang.org/x/net/http2".
+func (r *GoVanityResolver) ResolveRepository(ctx context.Context, importPath string) (string, error) {
+	if repository := builtinGoRepository(importPath); repository != "" {
+		return repository, nil
+	}
+
+	if repository, ok := r.cached(importPath); ok {
+		return repository, nil
+	}
+
+	if r.fetcher == nil {
+		return "", fmt.Errorf("unknown vanity import path %q", importPath)
+	}
+
+	page, err := r.fetcher.FetchGoImportPage(ctx, importPath)
+	if err != nil {
+		return "", fmt.Errorf("fetching go-import meta tags: %w", err)
+	}
+
+	imports, sources, err := parseGoMetaTags(page)
+	if err != nil {
+		return "", fmt.Errorf("parsing go-import meta tags: %w", err)
+	}
+
+	goImport, err := matchGoImport(imports, importPath)
+	if err != nil {
+		return "", err
+	}
+
+	repository := repositoryFromURL(goImport.repoRoot)
+
+	// the go-source meta tag is what pkg.go.dev uses to link to the source code;
+	// it is especially useful when go-import points to a host deps.dev does not know about
+	// (golang.org/x/* modules point to go.googlesource.com for instance)
+	// while the source is mirrored on GitHub
+	for _, source := range sources {
+		if source.prefix != goImport.prefix {
+			continue
+		}
+
+		sourceRepository := repositoryFromURL(source.home)
+		if isWellKnownRepositoryHost(sourceRepository) {
+			repository = sourceRepository
+		}
+		break
+	}
+
+	if repository == "" {
+		return "", fmt.Errorf("invalid repository root in go-import meta tag: %q", goImport.repoRoot)
+	}
+
+	r.cacheMutex.Lock()
+	r.cache[goImport.prefix] = repository
+	r.cacheMutex.Unlock()
+
+	return repository, nil
+}
+
+func (r *GoVanityResolver) cached(importPath string) (string, bool) {
+	r.cacheMutex.Lock()
+	defer r.cacheMutex.Unlock()
+
+	for prefix, repository := range r.cache {
+		if hasPathPrefix(importPath, prefix) {
+			return repository, true
+		}
+	}
+
+	return "", false
+}
+
+type goImportMetaTag struct {
+	prefix   string
+	vcs      string
+	repoRoot string
+}
+
+type goSourceMetaTag struct {
+	prefix string
+	home   string
+}
+
+// parseGoMetaTags is inspired by parseMetaGoImports in cmd/go,
+// using a non-strict XML decoder because HTML pages are rarely valid XML
+func parseGoMetaTags(page []byte) ([]goImportMetaTag, []goSourceMetaTag, error) {
+	decoder := xml.NewDecoder(strings.NewReader(string(page)))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var imports []goImportMetaTag
+	var sources []goSourceMetaTag
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if element, ok := token.(xml.StartElement); ok && strings.EqualFold(element.Name.Local, "body") {
+			break
+		}
+		if element, ok := token.(xml.EndElement); ok && strings.EqualFold(element.Name.Local, "head") {
+			break
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || !strings.EqualFold(element.Name.Local, "meta") {
+			continue
+		}
+
+		var name, content string
+		for _, attr := range element.Attr {
+			switch strings.ToLower(attr.Name.Local) {
+			case "name":
+				name = attr.Value
+			case "content":
+				content = attr.Value
+			}
+		}
+
+		fields := strings.Fields(content)
+
+		switch name {
+		case "go-import":
+			if len(fields) != 3 {
+				continue
+			}
+			imports = append(imports, goImportMetaTag{prefix: fields[0], vcs: fields[1], repoRoot: fields[2]})
+		case "go-source":
+			if len(fields) < 2 {
+				continue
+			}
+			sources = append(sources, goSourceMetaTag{prefix: fields[0], home: fields[1]})
+		}
+	}
+
+	return imports, sources, nil
+}
+
+// matchGoImport selects the go-import meta tag applying to importPath,
+// ignoring "mod" tags that point to a module proxy instead of a repository
+func matchGoImport(imports []goImportMetaTag, importPath string) (goImportMetaTag, error) {
+	var match *goImportMetaTag
+
+	for i := range imports {
+		if imports[i].vcs == "mod" || !hasPathPrefix(importPath, imports[i].prefix) {
+			continue
+		}
+
+		if match != nil && match.prefix != imports[i].prefix {
+			return goImportMetaTag{}, fmt.Errorf("multiple go-import meta tags match %q", importPath)
+		}
+
+		match = &imports[i]
+	}
+
+	if match == nil {
+		return goImportMetaTag{}, fmt.Errorf("no go-import meta tag matches %q", importPath)
+	}
+
+	return *match, nil
+}
+
+// repositoryFromURL turns "https://github.com/golang/net.git" into "github.com/golang/net"
+func repositoryFromURL(url string) string {
+	_, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		rest = url
+	}
+
+	rest = strings.TrimSuffix(rest, "/")
+	rest = strings.TrimSuffix(rest, ".git")
+
+	if isWellKnownRepositoryHost(rest) {
+		// drop paths such as "/tree/master"
+		elements := strings.Split(rest, "/")
+		if len(elements) > 3 {
+			rest = strings.Join(elements[:3], "/")
+		}
+	}
+
+	return rest
+}
+
+func isWellKnownRepositoryHost(repository string) bool {
+	host, _, _ := strings.Cut(repository, "/")
+	for _, h := range goRepositoryHosts {
+		if host == h {
+			return true
+		}
+	}
+
+	return false
+}
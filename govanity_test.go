@@ -0,0 +1,123 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinGoRepository(t *testing.T) {
+	for _, each := range []struct {
+		importPath string
+		expected   string
+	}{
+		{"golang.org/x/net", "github.com/golang/net"},
+		{"golang.org/x/net/http2", "github.com/golang/net"},
+		{"k8s.io/klog/v2", "github.com/kubernetes/klog"},
+		{"sigs.k8s.io/yaml", "github.com/kubernetes-sigs/yaml"},
+		{"go.uber.org/zap", "github.com/uber-go/zap"},
+		{"google.golang.org/grpc", "github.com/grpc/grpc-go"},
+		{"google.golang.org/grpc/credentials", "github.com/grpc/grpc-go"},
+		{"google.golang.org/genproto/googleapis/api", "github.com/googleapis/go-genproto"},
+		{"gopkg.in/yaml.v3", "github.com/go-yaml/yaml"},
+		{"gopkg.in/DataDog/dd-trace-go.v1/ddtrace", "github.com/DataDog/dd-trace-go"},
+		{"github.com/DataDog/aggregated-dependency-score/cmd/depscore", "github.com/DataDog/aggregated-dependency-score"},
+		{"example.com/unknown", ""},
+		{"google.golang.org/grpcfoo", ""},
+	} {
+		t.Run(each.importPath, func(t *testing.T) {
+			actual := builtinGoRepository(each.importPath)
+			if actual != each.expected {
+				t.Errorf("Expected %q, but got %q", each.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGoVanityResolverWithoutFetcher(t *testing.T) {
+	resolver := NewGoVanityResolver(nil)
+
+	actual, err := resolver.ResolveRepository(context.Background(), "golang.org/x/net/http2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != "github.com/golang/net" {
+		t.Errorf("Expected %q, but got %q", "github.com/golang/net", actual)
+	}
+
+	_, err = resolver.ResolveRepository(context.Background(), "example.com/unknown")
+	if err == nil {
+		t.Errorf("Expected error, but got nil")
+	}
+}
+
+func TestGoVanityResolver(t *testing.T) {
+	nbRequests := 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nbRequests++
+
+		if r.URL.Query().Get("go-get") != "1" {
+			http.Error(w, "missing go-get parameter", http.StatusBadRequest)
+			return
+		}
+
+		host := r.Host
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/mirrored"):
+			fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<meta name="go-import" content="%[1]s/mirrored git https://git.example.com/mirrored">
+<meta name="go-import" content="%[1]s/mirrored mod https://proxy.example.com">
+<meta name="go-source" content="%[1]s/mirrored https://github.com/example/mirrored/ https://github.com/example/mirrored/tree/master{/dir}">
+</head>
+<body>
+<meta name="go-import" content="%[1]s/mirrored git https://github.com/attacker/ignored-after-head">
+</body>
+</html>`, host)
+		case strings.HasPrefix(r.URL.Path, "/direct"):
+			fmt.Fprintf(w, `<html><head><meta name="go-import" content="%s/direct git https://gitlab.com/example/direct.git"></head></html>`, host)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	resolver := NewGoVanityResolver(&HTTPGoImportFetcher{Client: server.Client()})
+	ctx := context.Background()
+
+	for _, each := range []struct {
+		importPath string
+		expected   string
+	}{
+		{host + "/mirrored", "github.com/example/mirrored"},
+		{host + "/mirrored/subpackage", "github.com/example/mirrored"},
+		{host + "/direct/v2", "gitlab.com/example/direct"},
+	} {
+		t.Run(each.importPath, func(t *testing.T) {
+			actual, err := resolver.ResolveRepository(ctx, each.importPath)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != each.expected {
+				t.Errorf("Expected %q, but got %q", each.expected, actual)
+			}
+		})
+	}
+
+	// the subpackage was resolved from the cache
+	if nbRequests != 2 {
+		t.Errorf("Expected 2 requests, but got %d", nbRequests)
+	}
+
+	_, err := resolver.ResolveRepository(ctx, host+"/unknown")
+	if err == nil {
+		t.Errorf("Expected error, but got nil")
+	}
+}
@@ -0,0 +1,346 @@
+package aggregdepscore
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Graph is the dependency graph of an evaluation, as resolved by the DependencyResolver.
+// Unlike in the tree of evaluated packages (see WithEvaluationTree),
+// a package reachable through several paths is a single node.
+type Graph struct {
+	// Nodes are in the order of their first evaluation, the root first
+	Nodes []*GraphNode
+	Edges []GraphEdge
+}
+
+// GraphNode is a package of a Graph.
+// When the evaluations of a package differ between paths (because of truncations or cycles),
+// the values are the ones of the lowest aggregated trustworthiness.
+type GraphNode struct {
+	Package                      Package
+	IntrinsicTrustworthiness     float64
+	AggregatedTrustworthiness    float64
+	LogAggregatedTrustworthiness float64
+	// Depth is the lowest depth of the package
+	Depth int
+	// NbPaths is the number of paths from the root to the package
+	NbPaths int
+	// Fallback is set if the intrinsic trustworthiness of the package was assumed
+	Fallback *Fallback
+	// Truncation is set if the dependencies of the package were not evaluated (on some paths)
+	Truncation *Truncation
+}
+
+// GraphEdge is a dependency of a Graph.
+type GraphEdge struct {
+	From Package
+	To   Package
+	// Factor is the factor the dependency multiplies the aggregated trustworthiness of the dependent by,
+	// that is T'^e for the aggregated trustworthiness T' of the dependency, or 1 if Cycle is set
+	Factor float64
+	// Cycle is set if the edge closes a dependency cycle, which is ignored by the evaluation
+	Cycle bool
+}
+
+// Graph merges the nodes of the tree of node for the same package.
+func (n *Node) Graph() *Graph {
+	g := &Graph{}
+	nodes := make(map[Package]*GraphNode)
+	// index of the edges in g.Edges
+	edges := make(map[[2]Package]int)
+
+	var walk func(node *Node, parent *Node)
+	walk = func(node *Node, parent *Node) {
+		if parent != nil {
+			key := [2]Package{parent.Package, node.Package}
+			if i, ok := edges[key]; ok {
+				// the factor may differ between paths: keep the lowest
+				g.Edges[i].Factor = math.Min(g.Edges[i].Factor, node.Penalty)
+				g.Edges[i].Cycle = g.Edges[i].Cycle && node.Cycle != nil
+			} else {
+				edges[key] = len(g.Edges)
+				g.Edges = append(g.Edges, GraphEdge{From: parent.Package, To: node.Package, Factor: node.Penalty, Cycle: node.Cycle != nil})
+			}
+		}
+
+		if node.Cycle != nil {
+			return
+		}
+
+		graphNode, ok := nodes[node.Package]
+		if !ok {
+			graphNode = &GraphNode{Package: node.Package, Depth: node.Depth}
+			nodes[node.Package] = graphNode
+			g.Nodes = append(g.Nodes, graphNode)
+		}
+
+		if graphNode.NbPaths == 0 || node.LogAggregatedTrustworthiness < graphNode.LogAggregatedTrustworthiness {
+			graphNode.IntrinsicTrustworthiness = node.IntrinsicTrustworthiness
+			graphNode.AggregatedTrustworthiness = node.AggregatedTrustworthiness
+			graphNode.LogAggregatedTrustworthiness = node.LogAggregatedTrustworthiness
+		}
+		graphNode.NbPaths++
+		graphNode.Depth = min(graphNode.Depth, node.Depth)
+		if graphNode.Fallback == nil {
+			graphNode.Fallback = node.Fallback
+		}
+		if graphNode.Truncation == nil {
+			graphNode.Truncation = node.Truncation
+		}
+
+		for _, child := range node.Children {
+			walk(child, node)
+		}
+	}
+	walk(n, nil)
+
+	return g
+}
+
+// ids returns the identifiers of the nodes in the exported formats
+func (g *Graph) ids() map[Package]string {
+	ids := make(map[Package]string)
+	for i, node := range g.Nodes {
+		ids[node.Package] = "n" + strconv.Itoa(i)
+	}
+
+	return ids
+}
+
+func graphLabel(p Package) string {
+	label := p.Name
+	if p.Ecosystem != "" {
+		label = p.Ecosystem + " " + label
+	}
+	if p.Version != "" {
+		label += "@" + p.Version
+	}
+
+	return label
+}
+
+// WriteDOT writes g in the DOT language of Graphviz.
+// Nodes are colored from red to green according to the score of their aggregated trustworthiness.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	converter := &DefaultScoreTrustworthinessConverter{}
+	ids := g.ids()
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled];\n")
+
+	for _, node := range g.Nodes {
+		score := converter.ScoreFromTrustworthiness(node.AggregatedTrustworthiness)
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=\"%.3f 0.5 0.95\", intrinsic=%s, aggregated=%s, depth=%d, nb_paths=%d",
+			ids[node.Package],
+			dotQuote(fmt.Sprintf("%s\nintrinsic %.3g\naggregated %.3g", graphLabel(node.Package), node.IntrinsicTrustworthiness, node.AggregatedTrustworthiness)),
+			score/3,
+			strconv.FormatFloat(node.IntrinsicTrustworthiness, 'g', -1, 64),
+			strconv.FormatFloat(node.AggregatedTrustworthiness, 'g', -1, 64),
+			node.Depth,
+			node.NbPaths,
+		)
+		if node.Fallback != nil {
+			b.WriteString(", fallback=true")
+		}
+		if node.Truncation != nil {
+			b.WriteString(", truncated=true")
+		}
+		b.WriteString("];\n")
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=\"×%.3g\", factor=%s", ids[edge.From], ids[edge.To], edge.Factor, strconv.FormatFloat(edge.Factor, 'g', -1, 64))
+		if edge.Cycle {
+			b.WriteString(", cycle=true, style=dashed")
+		}
+		b.WriteString("];\n")
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a quoted DOT identifier
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLKeys = []graphMLKey{
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "ecosystem", For: "node", Name: "ecosystem", Type: "string"},
+	{ID: "name", For: "node", Name: "name", Type: "string"},
+	{ID: "version", For: "node", Name: "version", Type: "string"},
+	{ID: "intrinsic", For: "node", Name: "intrinsic_trustworthiness", Type: "double"},
+	{ID: "aggregated", For: "node", Name: "aggregated_trustworthiness", Type: "double"},
+	{ID: "depth", For: "node", Name: "depth", Type: "int"},
+	{ID: "nb_paths", For: "node", Name: "nb_paths", Type: "int"},
+	{ID: "fallback", For: "node", Name: "fallback", Type: "boolean"},
+	{ID: "truncated", For: "node", Name: "truncated", Type: "boolean"},
+	{ID: "factor", For: "edge", Name: "factor", Type: "double"},
+	{ID: "cycle", For: "edge", Name: "cycle", Type: "boolean"},
+}
+
+// WriteGraphML writes g in the GraphML format, for instance for Gephi.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	ids := g.ids()
+
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{ID: "dependencies", EdgeDefault: "directed"},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: ids[node.Package],
+			Data: []graphMLData{
+				{"label", graphLabel(node.Package)},
+				{"ecosystem", node.Package.Ecosystem},
+				{"name", node.Package.Name},
+				{"version", node.Package.Version},
+				{"intrinsic", strconv.FormatFloat(node.IntrinsicTrustworthiness, 'g', -1, 64)},
+				{"aggregated", strconv.FormatFloat(node.AggregatedTrustworthiness, 'g', -1, 64)},
+				{"depth", strconv.Itoa(node.Depth)},
+				{"nb_paths", strconv.Itoa(node.NbPaths)},
+				{"fallback", strconv.FormatBool(node.Fallback != nil)},
+				{"truncated", strconv.FormatBool(node.Truncation != nil)},
+			},
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: ids[edge.From],
+			Target: ids[edge.To],
+			Data: []graphMLData{
+				{"factor", strconv.FormatFloat(edge.Factor, 'g', -1, 64)},
+				{"cycle", strconv.FormatBool(edge.Cycle)},
+			},
+		})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+type graphJSON struct {
+	Nodes []graphJSONNode `json:"nodes"`
+	Edges []graphJSONEdge `json:"edges"`
+}
+
+type graphJSONNode struct {
+	ID                        string  `json:"id"`
+	Ecosystem                 string  `json:"ecosystem"`
+	Name                      string  `json:"name"`
+	Version                   string  `json:"version"`
+	IntrinsicTrustworthiness  float64 `json:"intrinsic_trustworthiness"`
+	AggregatedTrustworthiness float64 `json:"aggregated_trustworthiness"`
+	Depth                     int     `json:"depth"`
+	NbPaths                   int     `json:"nb_paths"`
+	Fallback                  bool    `json:"fallback"`
+	Truncated                 bool    `json:"truncated"`
+}
+
+type graphJSONEdge struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Factor float64 `json:"factor"`
+	Cycle  bool    `json:"cycle"`
+}
+
+// WriteJSON writes g as a JSON object with "nodes" and "edges" arrays,
+// edges referencing nodes by their "id".
+func (g *Graph) WriteJSON(w io.Writer) error {
+	ids := g.ids()
+
+	doc := graphJSON{Nodes: []graphJSONNode{}, Edges: []graphJSONEdge{}}
+
+	for _, node := range g.Nodes {
+		doc.Nodes = append(doc.Nodes, graphJSONNode{
+			ID:                        ids[node.Package],
+			Ecosystem:                 node.Package.Ecosystem,
+			Name:                      node.Package.Name,
+			Version:                   node.Package.Version,
+			IntrinsicTrustworthiness:  node.IntrinsicTrustworthiness,
+			AggregatedTrustworthiness: node.AggregatedTrustworthiness,
+			Depth:                     node.Depth,
+			NbPaths:                   node.NbPaths,
+			Fallback:                  node.Fallback != nil,
+			Truncated:                 node.Truncation != nil,
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Edges = append(doc.Edges, graphJSONEdge{
+			Source: ids[edge.From],
+			Target: ids[edge.To],
+			Factor: edge.Factor,
+			Cycle:  edge.Cycle,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
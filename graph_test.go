@@ -0,0 +1,122 @@
+package aggregdepscore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testGraph(t *testing.T) *Graph {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.95, "C": 0.8},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"B", "C"}, "B": {"C", "A"}, "C": {}},
+		},
+		WithEvaluationTree(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return evaluation.Tree.Graph()
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph(t)
+
+	var nodes []string
+	for _, node := range g.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%s depth %d, %d paths", node.Package.Name, node.Depth, node.NbPaths))
+	}
+
+	expectedNodes := []string{"A depth 0, 1 paths", "B depth 1, 1 paths", "C depth 1, 2 paths"}
+	if fmt.Sprint(nodes) != fmt.Sprint(expectedNodes) {
+		t.Errorf("expected nodes %q, got %q", expectedNodes, nodes)
+	}
+
+	var edges []string
+	for _, edge := range g.Edges {
+		edges = append(edges, fmt.Sprintf("%s->%s %.4f %t", edge.From.Name, edge.To.Name, edge.Factor, edge.Cycle))
+	}
+
+	expectedEdges := []string{
+		fmt.Sprintf("A->B %.4f false", math.Pow(0.95*math.Pow(0.8, 1.5), 1.5)),
+		fmt.Sprintf("B->C %.4f false", math.Pow(0.8, 1.5)),
+		"B->A 1.0000 true",
+		fmt.Sprintf("A->C %.4f false", math.Pow(0.8, 1.5)),
+	}
+	if fmt.Sprint(edges) != fmt.Sprint(expectedEdges) {
+		t.Errorf("expected edges %q, got %q", expectedEdges, edges)
+	}
+}
+
+func TestGraphExport(t *testing.T) {
+	g := testGraph(t)
+
+	var dot bytes.Buffer
+	err := g.WriteDOT(&dot)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"digraph dependencies {", `n0 [label="A\nintrinsic 0.9`, "n1 -> n0 [label=\"×1\", factor=1, cycle=true, style=dashed];"} {
+		if !strings.Contains(dot.String(), expected) {
+			t.Errorf("expected DOT output to contain %q, got:\n%s", expected, dot.String())
+		}
+	}
+
+	var graphML bytes.Buffer
+	err = g.WriteGraphML(&graphML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsedGraphML struct {
+		Nodes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+		} `xml:"graph>edge"`
+	}
+	err = xml.Unmarshal(graphML.Bytes(), &parsedGraphML)
+	if err != nil {
+		t.Fatalf("invalid GraphML: %v", err)
+	}
+	if len(parsedGraphML.Nodes) != 3 || len(parsedGraphML.Edges) != 4 {
+		t.Errorf("expected 3 nodes and 4 edges in GraphML, got:\n%s", graphML.String())
+	}
+
+	var graphJSON bytes.Buffer
+	err = g.WriteJSON(&graphJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsedJSON struct {
+		Nodes []map[string]any `json:"nodes"`
+		Edges []map[string]any `json:"edges"`
+	}
+	err = json.Unmarshal(graphJSON.Bytes(), &parsedJSON)
+	if err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(parsedJSON.Nodes) != 3 || len(parsedJSON.Edges) != 4 {
+		t.Errorf("expected 3 nodes and 4 edges in JSON, got:\n%s", graphJSON.String())
+	}
+	if parsedJSON.Edges[2]["source"] != "n1" || parsedJSON.Edges[2]["target"] != "n0" || parsedJSON.Edges[2]["cycle"] != true {
+		t.Errorf("unexpected cycle edge: %v", parsedJSON.Edges[2])
+	}
+}
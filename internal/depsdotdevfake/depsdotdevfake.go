@@ -0,0 +1,208 @@
+// Package depsdotdevfake provides an in-process fake of the deps.dev Insights API
+// so that code using the deps.dev client can be tested without network access.
+package depsdotdevfake
+
+import (
+	"context"
+	"sync"
+
+	api "deps.dev/api/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fake implements api.InsightsClient using data registered with its Add* methods.
+// Unknown packages and projects result in a NotFound error, like the real API.
+type Fake struct {
+	mutex        sync.Mutex
+	versions     map[versionKey]*api.Version
+	dependencies map[versionKey][]*api.Dependencies_Node
+	requirements map[versionKey]*api.Requirements
+	projects     map[string]*api.Project
+	calls        map[string]int
+}
+
+// compile-time interface checks
+var _ api.InsightsClient = &Fake{}
+
+type versionKey struct {
+	system  api.System
+	name    string
+	version string
+}
+
+func keyOf(k *api.VersionKey) versionKey {
+	if k == nil {
+		return versionKey{}
+	}
+
+	return versionKey{system: k.System, name: k.Name, version: k.Version}
+}
+
+// New creates a Fake with no data.
+func New() *Fake {
+	return &Fake{
+		versions:     make(map[versionKey]*api.Version),
+		dependencies: make(map[versionKey][]*api.Dependencies_Node),
+		requirements: make(map[versionKey]*api.Requirements),
+		projects:     make(map[string]*api.Project),
+		calls:        make(map[string]int),
+	}
+}
+
+// AddVersion registers a package version and the source repositories it is related to;
+// dependencies are direct dependencies of the package version.
+func (f *Fake) AddVersion(system api.System, name, version string, repositories []string, dependencies ...*api.VersionKey) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := &api.VersionKey{System: system, Name: name, Version: version}
+
+	v := &api.Version{VersionKey: key}
+	for _, repository := range repositories {
+		v.RelatedProjects = append(v.RelatedProjects, &api.Version_Project{
+			ProjectKey:         &api.ProjectKey{Id: repository},
+			RelationType:       api.ProjectRelationType_SOURCE_REPO,
+			RelationProvenance: api.ProjectRelationProvenance_UNVERIFIED_METADATA,
+		})
+	}
+	f.versions[keyOf(key)] = v
+
+	nodes := []*api.Dependencies_Node{{VersionKey: key, Relation: api.DependencyRelation_SELF}}
+	for _, dep := range dependencies {
+		nodes = append(nodes, &api.Dependencies_Node{VersionKey: dep, Relation: api.DependencyRelation_DIRECT})
+	}
+	f.dependencies[keyOf(key)] = nodes
+}
+
+// AddVersionDetails replaces the version registered with AddVersion,
+// for tests that need fields AddVersion does not set (links, provenance...).
+func (f *Fake) AddVersionDetails(v *api.Version) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.versions[keyOf(v.VersionKey)] = v
+}
+
+// AddBundledDependencies registers npm bundled dependencies of a package version.
+func (f *Fake) AddBundledDependencies(name, version string, bundled ...*api.Requirements_NPM_Bundle) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := versionKey{system: api.System_NPM, name: name, version: version}
+
+	f.dependencies[key] = append(f.dependencies[key], &api.Dependencies_Node{
+		VersionKey: &api.VersionKey{System: api.System_NPM, Name: "bundled", Version: "bundled"},
+		Relation:   api.DependencyRelation_DIRECT,
+		Bundled:    true,
+	})
+
+	f.requirements[key] = &api.Requirements{Npm: &api.Requirements_NPM{Bundled: bundled}}
+}
+
+// AddProject registers a project with the given OSSF scorecard overall score.
+func (f *Fake) AddProject(repository string, overallScore float32) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.projects[repository] = &api.Project{
+		ProjectKey: &api.ProjectKey{Id: repository},
+		Scorecard:  &api.Project_Scorecard{OverallScore: overallScore},
+	}
+}
+
+// Calls returns how many times the given method (e.g. "GetProject") was called.
+func (f *Fake) Calls(method string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.calls[method]
+}
+
+func (f *Fake) call(method string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls[method]++
+}
+
+func (f *Fake) GetVersion(ctx context.Context, in *api.GetVersionRequest, opts ...grpc.CallOption) (*api.Version, error) {
+	f.call("GetVersion")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	v, ok := f.versions[keyOf(in.VersionKey)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "version not found: %v", in.VersionKey)
+	}
+
+	return v, nil
+}
+
+func (f *Fake) GetDependencies(ctx context.Context, in *api.GetDependenciesRequest, opts ...grpc.CallOption) (*api.Dependencies, error) {
+	f.call("GetDependencies")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	nodes, ok := f.dependencies[keyOf(in.VersionKey)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "version not found: %v", in.VersionKey)
+	}
+
+	return &api.Dependencies{Nodes: nodes}, nil
+}
+
+func (f *Fake) GetRequirements(ctx context.Context, in *api.GetRequirementsRequest, opts ...grpc.CallOption) (*api.Requirements, error) {
+	f.call("GetRequirements")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	requirements, ok := f.requirements[keyOf(in.VersionKey)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "requirements not found: %v", in.VersionKey)
+	}
+
+	return requirements, nil
+}
+
+func (f *Fake) GetProject(ctx context.Context, in *api.GetProjectRequest, opts ...grpc.CallOption) (*api.Project, error) {
+	f.call("GetProject")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if in.ProjectKey == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing project key")
+	}
+
+	project, ok := f.projects[in.ProjectKey.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "project not found: %s", in.ProjectKey.Id)
+	}
+
+	return project, nil
+}
+
+func (f *Fake) GetPackage(ctx context.Context, in *api.GetPackageRequest, opts ...grpc.CallOption) (*api.Package, error) {
+	f.call("GetPackage")
+	return nil, status.Error(codes.Unimplemented, "not implemented by the fake")
+}
+
+func (f *Fake) GetProjectPackageVersions(ctx context.Context, in *api.GetProjectPackageVersionsRequest, opts ...grpc.CallOption) (*api.ProjectPackageVersions, error) {
+	f.call("GetProjectPackageVersions")
+	return nil, status.Error(codes.Unimplemented, "not implemented by the fake")
+}
+
+func (f *Fake) GetAdvisory(ctx context.Context, in *api.GetAdvisoryRequest, opts ...grpc.CallOption) (*api.Advisory, error) {
+	f.call("GetAdvisory")
+	return nil, status.Error(codes.Unimplemented, "not implemented by the fake")
+}
+
+func (f *Fake) Query(ctx context.Context, in *api.QueryRequest, opts ...grpc.CallOption) (*api.QueryResult, error) {
+	f.call("Query")
+	return nil, status.Error(codes.Unimplemented, "not implemented by the fake")
+}
@@ -0,0 +1,163 @@
+package aggregdepscore
+
+import "fmt"
+
+// TruncationPolicy decides what happens to a package
+// that would be evaluated beyond one of the Limits.
+type TruncationPolicy int
+
+const (
+	// TruncateFail makes the evaluation fail with an *ErrLimitExceeded error
+	TruncateFail TruncationPolicy = iota
+	// TruncateAssumeTrustworthiness assumes Limits.AssumedTrustworthiness
+	// as the aggregated trustworthiness of the truncated subtree
+	TruncateAssumeTrustworthiness
+	// TruncateIntrinsicOnly evaluates the intrinsic trustworthiness of the package
+	// but ignores its dependencies
+	// (see Limits for how MaxNodes and MaxCalls account for it)
+	TruncateIntrinsicOnly
+)
+
+func (p TruncationPolicy) String() string {
+	switch p {
+	case TruncateFail:
+		return "fail"
+	case TruncateAssumeTrustworthiness:
+		return "assume"
+	case TruncateIntrinsicOnly:
+		return "intrinsic-only"
+	default:
+		return fmt.Sprintf("TruncationPolicy(%d)", int(p))
+	}
+}
+
+// Limits bounds the work done by an evaluation.
+// Because packages are evaluated once per path from the root (see the design paper),
+// pathological dependency graphs can otherwise take a very long time to evaluate.
+//
+// A zero value means no limit.
+type Limits struct {
+	// MaxDepth is the maximum depth of fully evaluated packages;
+	// the root has depth 0 and its direct dependencies depth 1.
+	// Whatever the policy, the packages at depth MaxDepth+1 are the truncated ones:
+	// with TruncateIntrinsicOnly only their intrinsic trustworthiness is evaluated,
+	// otherwise they are never looked up.
+	MaxDepth int
+	// MaxNodes is the maximum number of package evaluations,
+	// including those of packages truncated with TruncateIntrinsicOnly;
+	// a package reachable through several paths is counted once per path
+	MaxNodes int
+	// MaxCalls is the maximum number of calls to the intrinsic trustworthiness evaluator
+	// and to the dependency resolver (usually, one RPC each);
+	// lookups answered by a batch evaluation or by the cache (see WithCache) are not counted.
+	//
+	// With TruncateIntrinsicOnly, evaluating a dependency takes at least one node and one call
+	// so they are set aside when its dependent is evaluated:
+	// if the dependencies of a package do not fit within MaxNodes and MaxCalls,
+	// that package is truncated instead and its dependencies are ignored.
+	MaxCalls int
+
+	Policy TruncationPolicy
+	// AssumedTrustworthiness is used with TruncateAssumeTrustworthiness;
+	// it must be greater than 0 (the logarithm of 0 is infinite) and at most 1
+	AssumedTrustworthiness float64
+}
+
+// Limit names used in Truncation and ErrLimitExceeded
+const (
+	LimitDepth = "depth"
+	LimitNodes = "nodes"
+	LimitCalls = "calls"
+)
+
+// Truncation records that the subtree rooted at a package was not fully evaluated.
+type Truncation struct {
+	Package Package
+	Depth   int
+	// Limit is one of LimitDepth, LimitNodes and LimitCalls
+	Limit  string
+	Policy TruncationPolicy
+}
+
+// ErrLimitExceeded is returned when a limit is exceeded and the policy is TruncateFail.
+type ErrLimitExceeded struct {
+	Limit   string
+	Value   int
+	Package Package
+}
+
+func (e *ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s limit (%d) exceeded when evaluating %s", e.Limit, e.Value, e.Package)
+}
+
+func (l *Limits) validate() error {
+	if l.MaxDepth < 0 || l.MaxNodes < 0 || l.MaxCalls < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+
+	switch l.Policy {
+	case TruncateFail, TruncateIntrinsicOnly:
+	case TruncateAssumeTrustworthiness:
+		if !(l.AssumedTrustworthiness > 0 && l.AssumedTrustworthiness <= 1) {
+			return fmt.Errorf("assumed trustworthiness must be greater than 0 and at most 1, got %g", l.AssumedTrustworthiness)
+		}
+	default:
+		return fmt.Errorf("unknown truncation policy: %v", l.Policy)
+	}
+
+	return nil
+}
+
+// exceeded returns the name of the limit that fully evaluating one more package at the given depth,
+// with the given number of calls, would exceed, along with the value of the limit, or an empty string.
+// A full evaluation takes up to two calls:
+// one for the intrinsic trustworthiness and one for the dependencies.
+func (l *Limits) exceeded(state *evaluationState, depth int, nbCalls int) (string, int) {
+	if l == nil {
+		return "", 0
+	}
+
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return LimitDepth, l.MaxDepth
+	}
+
+	if l.MaxNodes > 0 && state.nbNodes+state.nbReserved >= l.MaxNodes {
+		return LimitNodes, l.MaxNodes
+	}
+
+	if l.MaxCalls > 0 && state.nbCalls+state.nbReserved+nbCalls > l.MaxCalls {
+		return LimitCalls, l.MaxCalls
+	}
+
+	return "", 0
+}
+
+// reserve sets aside a node and a call for each of the given number of dependencies
+// with TruncateIntrinsicOnly, so that they can at least be evaluated intrinsically;
+// if that would exceed a limit, it returns its name and value and reserves nothing
+func (l *Limits) reserve(state *evaluationState, nbDependencies int) (string, int) {
+	if l == nil || l.Policy != TruncateIntrinsicOnly {
+		return "", 0
+	}
+
+	if l.MaxNodes > 0 && state.nbNodes+state.nbReserved+nbDependencies > l.MaxNodes {
+		return LimitNodes, l.MaxNodes
+	}
+
+	if l.MaxCalls > 0 && state.nbCalls+state.nbReserved+nbDependencies > l.MaxCalls {
+		return LimitCalls, l.MaxCalls
+	}
+
+	state.nbReserved += nbDependencies
+
+	return "", 0
+}
+
+// release gives back what was reserved for a dependency about to be evaluated
+func (l *Limits) release(state *evaluationState) {
+	if l == nil || l.Policy != TruncateIntrinsicOnly {
+		return
+	}
+
+	state.nbReserved--
+}
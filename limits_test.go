@@ -0,0 +1,274 @@
+package aggregdepscore
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	intrinsic := map[string]float64{
+		"A": 0.9,
+		"B": 0.8,
+		"C": 0.7,
+		"D": 0.6,
+		"E": 0.95,
+	}
+	deps := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {"E"},
+		"E": {},
+	}
+
+	// aggregated computes T'(A) given T'(D)
+	aggregated := func(tPrimeD float64) float64 {
+		tPrimeB := intrinsic["B"] * math.Pow(tPrimeD, 1.5)
+		tPrimeC := intrinsic["C"] * math.Pow(tPrimeD, 1.5)
+		return intrinsic["A"] * math.Pow(tPrimeB, 1.5) * math.Pow(tPrimeC, 1.5)
+	}
+
+	testCases := []struct {
+		name          string
+		limits        Limits
+		expected      float64
+		expectedNodes int
+		// expectedCalls is checked if not zero
+		expectedCalls       int
+		expectedTruncations []Truncation
+		expectedError       *ErrLimitExceeded
+		// notLookedUp are packages whose intrinsic trustworthiness must not be evaluated
+		notLookedUp []string
+	}{
+		{
+			name:          "no limits",
+			limits:        Limits{},
+			expected:      aggregated(0.6 * math.Pow(0.95, 1.5)),
+			expectedNodes: 7,
+		},
+		{
+			name:          "depth, assume trustworthiness",
+			limits:        Limits{MaxDepth: 1, Policy: TruncateAssumeTrustworthiness, AssumedTrustworthiness: 0.5},
+			expected:      aggregated(0.5),
+			expectedNodes: 3,
+			notLookedUp:   []string{"D", "E"},
+			expectedTruncations: []Truncation{
+				{Package: Package{Name: "D"}, Depth: 2, Limit: LimitDepth, Policy: TruncateAssumeTrustworthiness},
+				{Package: Package{Name: "D"}, Depth: 2, Limit: LimitDepth, Policy: TruncateAssumeTrustworthiness},
+			},
+		},
+		{
+			// the same packages are truncated as with TruncateAssumeTrustworthiness
+			name:          "depth, intrinsic only",
+			limits:        Limits{MaxDepth: 1, Policy: TruncateIntrinsicOnly},
+			expected:      aggregated(intrinsic["D"]),
+			expectedNodes: 5,
+			expectedCalls: 8,
+			expectedTruncations: []Truncation{
+				{Package: Package{Name: "D"}, Depth: 2, Limit: LimitDepth, Policy: TruncateIntrinsicOnly},
+				{Package: Package{Name: "D"}, Depth: 2, Limit: LimitDepth, Policy: TruncateIntrinsicOnly},
+			},
+			notLookedUp: []string{"E"},
+		},
+		{
+			name:          "depth, deep enough",
+			limits:        Limits{MaxDepth: 3, Policy: TruncateAssumeTrustworthiness, AssumedTrustworthiness: 0.5},
+			expected:      aggregated(0.6 * math.Pow(0.95, 1.5)),
+			expectedNodes: 7,
+		},
+		{
+			name:          "depth, fail",
+			limits:        Limits{MaxDepth: 1},
+			expectedError: &ErrLimitExceeded{Limit: LimitDepth, Value: 1, Package: Package{Name: "D"}},
+		},
+		{
+			name:   "nodes, assume trustworthiness",
+			limits: Limits{MaxNodes: 3, Policy: TruncateAssumeTrustworthiness, AssumedTrustworthiness: 1},
+			// E and C are truncated
+			expected: intrinsic["A"] *
+				math.Pow(intrinsic["B"]*math.Pow(intrinsic["D"], 1.5), 1.5),
+			expectedNodes: 3,
+			expectedTruncations: []Truncation{
+				{Package: Package{Name: "E"}, Depth: 3, Limit: LimitNodes, Policy: TruncateAssumeTrustworthiness},
+				{Package: Package{Name: "C"}, Depth: 1, Limit: LimitNodes, Policy: TruncateAssumeTrustworthiness},
+			},
+		},
+		{
+			name:   "nodes, intrinsic only",
+			limits: Limits{MaxNodes: 3, Policy: TruncateIntrinsicOnly},
+			// the nodes of B and C are set aside when evaluating A,
+			// so D does not fit and B and C are truncated
+			expected:      intrinsic["A"] * math.Pow(intrinsic["B"], 1.5) * math.Pow(intrinsic["C"], 1.5),
+			expectedNodes: 3,
+			expectedCalls: 6,
+			expectedTruncations: []Truncation{
+				{Package: Package{Name: "B"}, Depth: 1, Limit: LimitNodes, Policy: TruncateIntrinsicOnly},
+				{Package: Package{Name: "C"}, Depth: 1, Limit: LimitNodes, Policy: TruncateIntrinsicOnly},
+			},
+			notLookedUp: []string{"D", "E"},
+		},
+		{
+			name:   "calls, intrinsic only",
+			limits: Limits{MaxCalls: 4, Policy: TruncateIntrinsicOnly},
+			// A takes two calls, and the intrinsic trustworthiness of B and C one each
+			expected:      intrinsic["A"] * math.Pow(intrinsic["B"], 1.5) * math.Pow(intrinsic["C"], 1.5),
+			expectedNodes: 3,
+			expectedCalls: 4,
+			expectedTruncations: []Truncation{
+				{Package: Package{Name: "B"}, Depth: 1, Limit: LimitCalls, Policy: TruncateIntrinsicOnly},
+				{Package: Package{Name: "C"}, Depth: 1, Limit: LimitCalls, Policy: TruncateIntrinsicOnly},
+			},
+			notLookedUp: []string{"D", "E"},
+		},
+		{
+			name: "calls, fail",
+			// A and B take two calls each
+			limits:        Limits{MaxCalls: 4},
+			expectedError: &ErrLimitExceeded{Limit: LimitCalls, Value: 4, Package: Package{Name: "D"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			intrinsicEvaluator := &testIntrinsicTrustworthinessEvaluator{trustworthinessByName: intrinsic}
+			resolver := &testDependencyResolver{directDependencyNamesByName: deps}
+			evaluator, err := NewEvaluator(
+				intrinsicEvaluator,
+				resolver,
+				WithLimits(tc.limits),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+
+			if tc.expectedError != nil {
+				var limitErr *ErrLimitExceeded
+				if !errors.As(err, &limitErr) {
+					t.Fatalf("expected ErrLimitExceeded, got %v", err)
+				}
+				if *limitErr != *tc.expectedError {
+					t.Errorf("expected error %v, got %v", tc.expectedError, limitErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if math.Abs(evaluation.Trustworthiness-tc.expected) > 1e-9 {
+				t.Errorf("expected trustworthiness %f, got %f", tc.expected, evaluation.Trustworthiness)
+			}
+
+			if evaluation.NbNodes != tc.expectedNodes {
+				t.Errorf("expected %d nodes, got %d", tc.expectedNodes, evaluation.NbNodes)
+			}
+
+			if tc.expectedCalls != 0 {
+				nbCalls := resolver.nbQueries
+				for _, n := range intrinsicEvaluator.nbQueryByPackage {
+					nbCalls += n
+				}
+				if nbCalls != tc.expectedCalls {
+					t.Errorf("expected %d calls, got %d", tc.expectedCalls, nbCalls)
+				}
+			}
+
+			for _, name := range tc.notLookedUp {
+				if intrinsicEvaluator.nbQueryByPackage[name] != 0 {
+					t.Errorf("expected %s not to be looked up beyond the maximum depth", name)
+				}
+			}
+
+			if evaluation.Truncated != (len(tc.expectedTruncations) > 0) {
+				t.Errorf("expected truncated to be %t", len(tc.expectedTruncations) > 0)
+			}
+
+			if len(evaluation.Truncations) != len(tc.expectedTruncations) {
+				t.Fatalf("expected truncations %v, got %v", tc.expectedTruncations, evaluation.Truncations)
+			}
+			for i := range tc.expectedTruncations {
+				if evaluation.Truncations[i] != tc.expectedTruncations[i] {
+					t.Errorf("expected truncation %v, got %v", tc.expectedTruncations[i], evaluation.Truncations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidLimits(t *testing.T) {
+	for _, limits := range []Limits{
+		{MaxDepth: -1},
+		{Policy: TruncateAssumeTrustworthiness, AssumedTrustworthiness: 1.5},
+		{Policy: TruncateAssumeTrustworthiness, AssumedTrustworthiness: 0},
+		{Policy: TruncationPolicy(42)},
+	} {
+		_, err := NewEvaluator(
+			&testIntrinsicTrustworthinessEvaluator{},
+			&testDependencyResolver{},
+			WithLimits(limits),
+		)
+		if err == nil {
+			t.Errorf("expected an error for limits %+v", limits)
+		}
+	}
+}
+
+func TestLimitsWithMemoizedLookups(t *testing.T) {
+	// D and E are reached through B and C:
+	// A, B, C, D and E take ten calls, and the second evaluations of D and E are memoized
+	deps := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {"E"},
+		"E": {},
+	}
+
+	testCases := []struct {
+		name          string
+		maxCalls      int
+		expectedError *ErrLimitExceeded
+	}{
+		{name: "enough calls", maxCalls: 10},
+		{name: "not enough calls", maxCalls: 9, expectedError: &ErrLimitExceeded{Limit: LimitCalls, Value: 9, Package: Package{Name: "C"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator, err := NewEvaluator(
+				&testIntrinsicTrustworthinessEvaluator{trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.8, "C": 0.7, "D": 0.6, "E": 0.95}},
+				&testDependencyResolver{directDependencyNamesByName: deps},
+				WithLimits(Limits{MaxCalls: tc.maxCalls}),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			results := evaluator.EvaluateScores(context.Background(), []Package{{Name: "A"}})
+			err = results[0].Err
+
+			if tc.expectedError == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if results[0].Evaluation.NbNodes != 7 {
+					t.Errorf("expected 7 nodes, got %d", results[0].Evaluation.NbNodes)
+				}
+				return
+			}
+
+			var limitErr *ErrLimitExceeded
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected ErrLimitExceeded, got %v", err)
+			}
+			if *limitErr != *tc.expectedError {
+				t.Errorf("expected error %v, got %v", tc.expectedError, limitErr)
+			}
+		})
+	}
+}
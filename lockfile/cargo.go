@@ -0,0 +1,110 @@
+package lockfile
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+type cargoPackage struct {
+	name         string
+	version      string
+	source       string
+	dependencies []string
+	line         int
+}
+
+// parseCargoLock reads the dependencies of the local packages of a Cargo.lock file
+// (the packages without a source, that is the workspace members),
+// other than the local packages themselves.
+func parseCargoLock(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	tables, err := parseTOML(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var packages []cargoPackage
+	for _, t := range tables {
+		if t.header != "package" || !t.array {
+			continue
+		}
+
+		packages = append(packages, cargoPackage{
+			name:         t.values["name"].str,
+			version:      t.values["version"].str,
+			source:       t.values["source"].str,
+			dependencies: t.values["dependencies"].list,
+			line:         t.values["name"].line,
+		})
+	}
+
+	local := make(map[string]bool)
+	for _, p := range packages {
+		if p.source == "" {
+			local[p.name] = true
+		}
+	}
+
+	seen := make(map[aggregdepscore.Package]bool)
+	var deps []Dependency
+
+	for _, p := range packages {
+		if p.source != "" {
+			continue
+		}
+
+		for _, reference := range p.dependencies {
+			dep, ok, err := resolveCargoDependency(packages, reference)
+			if err != nil {
+				return nil, nil, fmt.Errorf("dependency %q of %s: %w", reference, p.name, err)
+			}
+			if !ok || local[dep.name] {
+				continue
+			}
+
+			pkg := aggregdepscore.Package{Name: dep.name, Version: dep.version}
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+
+			deps = append(deps, Dependency{Package: pkg, Line: dep.line})
+		}
+	}
+
+	sort.SliceStable(deps, func(i, j int) bool { return deps[i].Line < deps[j].Line })
+
+	return deps, nil, nil
+}
+
+// resolveCargoDependency finds the package referenced by a dependency of a Cargo.lock package,
+// which is "name", "name version" or "name version (source)"
+func resolveCargoDependency(packages []cargoPackage, reference string) (cargoPackage, bool, error) {
+	fields := strings.Fields(reference)
+	if len(fields) == 0 {
+		return cargoPackage{}, false, fmt.Errorf("empty dependency")
+	}
+
+	var candidates []cargoPackage
+	for _, p := range packages {
+		if p.name != fields[0] {
+			continue
+		}
+		if len(fields) > 1 && p.version != fields[1] {
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return cargoPackage{}, false, nil
+	case 1:
+		return candidates[0], true, nil
+	default:
+		return cargoPackage{}, false, fmt.Errorf("ambiguous dependency")
+	}
+}
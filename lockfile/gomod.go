@@ -0,0 +1,89 @@
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// parseGoMod reads the require directives of a go.mod file,
+// ignoring the requirements marked as indirect.
+// Replace directives are not taken into account.
+func parseGoMod(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	inRequireBlock := false
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		comment := ""
+		if i := strings.Index(line, "//"); i >= 0 {
+			line, comment = line[:i], line[i+2:]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequireBlock && fields[0] == ")":
+			inRequireBlock = false
+			continue
+		case inRequireBlock:
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequireBlock = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		default:
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("line %d: invalid requirement: %q", lineNumber, line)
+		}
+
+		if strings.TrimSpace(comment) == "indirect" || strings.HasPrefix(strings.TrimSpace(comment), "indirect;") {
+			continue
+		}
+
+		module, err := unquoteGoMod(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		version, err := unquoteGoMod(fields[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		deps = append(deps, Dependency{
+			Package: aggregdepscore.Package{Name: module, Version: version},
+			Line:    lineNumber,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return deps, nil, nil
+}
+
+func unquoteGoMod(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "`") {
+		return strconv.Unquote(s)
+	}
+
+	return s, nil
+}
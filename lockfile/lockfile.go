@@ -0,0 +1,158 @@
+// Package lockfile reads the direct dependencies of a project
+// from its manifests and lockfiles.
+//
+// Only direct dependencies are read:
+// transitive dependencies are resolved by the aggregdepscore.DependencyResolver
+// of the evaluator (see aggregdepscore.Evaluator.EvaluateProject).
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// Dependency is a direct dependency declared in a file.
+type Dependency struct {
+	Package aggregdepscore.Package
+	// Line is the 1-based line where the dependency is declared, 0 if unknown
+	Line int
+}
+
+// File is a manifest or lockfile.
+type File struct {
+	// Path is the slash-separated path of the file
+	Path         string
+	Ecosystem    string
+	Dependencies []Dependency
+	// Warnings describe the dependencies that are declared but could not be read
+	Warnings []string
+}
+
+type parser struct {
+	ecosystem string
+	// parse returns the dependencies of a file and warnings about the ones it cannot read
+	parse func(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error)
+}
+
+// parsers by file name
+var parsers = map[string]parser{
+	"go.mod":             {"go", parseGoMod},
+	"package-lock.json":  {"npm", parsePackageLock},
+	"Cargo.lock":         {"crates.io", parseCargoLock},
+	"poetry.lock":        {"pypi", parsePoetryLock},
+	"pom.xml":            {"maven", parsePOM},
+	"packages.lock.json": {"nuget", parseNuGetLock},
+}
+
+// Filenames returns the names of the supported files.
+func Filenames() []string {
+	var result []string
+	for name := range parsers {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Supported tells if the file at name (a path) can be parsed.
+func Supported(name string) bool {
+	_, ok := parsers[path.Base(name)]
+	return ok
+}
+
+// ErrNoFiles is returned by Detect when no supported file is found.
+var ErrNoFiles = errors.New("no supported manifest or lockfile found")
+
+// Detect parses the supported files present at the root of fsys.
+func Detect(fsys fs.FS) ([]*File, error) {
+	var result []*File
+
+	for _, name := range Filenames() {
+		_, err := fs.Stat(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := Parse(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, f)
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNoFiles
+	}
+
+	return result, nil
+}
+
+// Parse parses the file at name in fsys;
+// the format is deduced from the base name of the file (see Filenames).
+// Other files of fsys may be read, for instance pyproject.toml next to poetry.lock.
+func Parse(fsys fs.FS, name string) (*File, error) {
+	p, ok := parsers[path.Base(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported file: %s", name)
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, warnings, err := p.parse(fsys, name, content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	for i := range deps {
+		deps[i].Package.Ecosystem = p.ecosystem
+	}
+
+	return &File{Path: name, Ecosystem: p.ecosystem, Dependencies: deps, Warnings: warnings}, nil
+}
+
+// Packages returns the packages of all files, without duplicates.
+func Packages(files []*File) []aggregdepscore.Package {
+	var result []aggregdepscore.Package
+	seen := make(map[aggregdepscore.Package]bool)
+
+	for _, f := range files {
+		for _, dep := range f.Dependencies {
+			if seen[dep.Package] {
+				continue
+			}
+			seen[dep.Package] = true
+			result = append(result, dep.Package)
+		}
+	}
+
+	return result
+}
+
+// lineOf returns the 1-based line of the first occurrence of needle in content at or after offset,
+// or 0 if there is none
+func lineOf(content []byte, needle string, offset int) int {
+	if offset > len(content) {
+		return 0
+	}
+
+	i := bytes.Index(content[offset:], []byte(needle))
+	if i < 0 {
+		return 0
+	}
+
+	return bytes.Count(content[:offset+i], []byte("\n")) + 1
+}
@@ -0,0 +1,297 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		expected         []string
+		expectedWarnings []string
+	}{
+		{
+			name: "go.mod",
+			files: map[string]string{
+				"go.mod": `module example.com/app
+
+go 1.23
+
+require github.com/google/uuid v1.6.0
+
+require (
+	deps.dev/api/v3 v3.0.0-20241010035105-b3ba03369df1
+	golang.org/x/text v0.17.0 // indirect
+	"gopkg.in/yaml.v3" v3.0.1 // a comment
+)
+
+replace example.com/other => ../other
+`,
+			},
+			expected: []string{
+				"5 go github.com/google/uuid v1.6.0",
+				"8 go deps.dev/api/v3 v3.0.0-20241010035105-b3ba03369df1",
+				"10 go gopkg.in/yaml.v3 v3.0.1",
+			},
+		},
+		{
+			name: "package-lock.json",
+			files: map[string]string{
+				"package-lock.json": `{
+  "name": "app",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "app",
+      "dependencies": {"express": "^4.18.0", "local": "file:./local"},
+      "optionalDependencies": {"fsevents": "^2.3.0"},
+      "devDependencies": {"jest": "^29.0.0"}
+    },
+    "node_modules/express": {"version": "4.18.2"},
+    "node_modules/jest": {"version": "29.7.0", "dev": true},
+    "node_modules/local": {"resolved": "local", "link": true}
+  }
+}`,
+			},
+			expected: []string{
+				"11 npm express 4.18.2",
+			},
+		},
+		{
+			name: "Cargo.lock",
+			files: map[string]string{
+				"Cargo.lock": `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+ "tool",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "abc"
+
+[[package]]
+name = "tool"
+version = "0.1.0"
+dependencies = [
+ "serde 1.0.200 (registry+https://github.com/rust-lang/crates.io-index)",
+]
+`,
+			},
+			expected: []string{
+				"13 crates.io serde 1.0.200",
+			},
+		},
+		{
+			name: "poetry.lock with pyproject.toml",
+			files: map[string]string{
+				"poetry.lock": `[[package]]
+name = "requests"
+version = "2.31.0"
+description = "Python HTTP for Humans."
+optional = false
+python-versions = ">=3.7"
+files = [
+    {file = "requests-2.31.0.tar.gz", hash = "sha256:abc"},
+]
+
+[package.dependencies]
+urllib3 = ">=1.21.1,<3"
+
+[[package]]
+name = "urllib3"
+version = "2.2.1"
+
+[[package]]
+name = "Typing_Extensions"
+version = "4.11.0"
+`,
+				"pyproject.toml": `[tool.poetry]
+name = "app"
+description = """
+A multi-line
+description"""
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.31"
+typing-extensions = {version = "^4.11", optional = true}
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^8"
+`,
+			},
+			expected: []string{
+				"2 pypi requests 2.31.0",
+				"19 pypi Typing_Extensions 4.11.0",
+			},
+		},
+		{
+			name: "pom.xml",
+			files: map[string]string{
+				"pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <jackson.version>2.17.0</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.managed</groupId>
+        <artifactId>managed</artifactId>
+        <version>1.0</version>
+      </dependency>
+      <dependency>
+        <groupId>org.managed</groupId>
+        <artifactId>managed-test</artifactId>
+        <version>2.0</version>
+        <scope>test</scope>
+      </dependency>
+      <dependency>
+        <groupId>org.bom</groupId>
+        <artifactId>bom</artifactId>
+        <version>3.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.managed</groupId>
+      <artifactId>managed</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.managed</groupId>
+      <artifactId>managed-test</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>sibling</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.bom</groupId>
+      <artifactId>from-bom</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.unknown</groupId>
+      <artifactId>unknown</artifactId>
+      <version>${unknown.version}</version>
+    </dependency>
+  </dependencies>
+</project>
+`,
+			},
+			expected: []string{
+				"36 maven org.managed:managed 1.0",
+				"40 maven com.fasterxml.jackson.core:jackson-databind 2.17.0",
+				"55 maven com.example:sibling 1.0.0",
+			},
+			expectedWarnings: []string{
+				"ignoring org.bom:from-bom (line 60): its version is not set in this file, for instance because it is managed by a parent POM",
+				"ignoring org.unknown:unknown (line 64): its version ${unknown.version} is not defined in this file",
+			},
+		},
+		{
+			name: "packages.lock.json",
+			files: map[string]string{
+				"packages.lock.json": `{
+  "version": 1,
+  "dependencies": {
+    "net6.0": {
+      "Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.3, )", "resolved": "13.0.3"},
+      "System.Memory": {"type": "Transitive", "resolved": "4.5.5"}
+    },
+    "net8.0": {
+      "Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.3, )", "resolved": "13.0.3"}
+    }
+  }
+}`,
+			},
+			expected: []string{
+				"5 nuget Newtonsoft.Json 13.0.3",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tc.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			files, err := Detect(fsys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(files) != 1 {
+				t.Fatalf("expected one file to be detected, got %d", len(files))
+			}
+
+			var actual []string
+			for _, dep := range files[0].Dependencies {
+				actual = append(actual, fmt.Sprintf("%d %s %s %s", dep.Line, dep.Package.Ecosystem, dep.Package.Name, dep.Package.Version))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+
+			if fmt.Sprint(files[0].Warnings) != fmt.Sprint(tc.expectedWarnings) {
+				t.Errorf("expected warnings %q, got %q", tc.expectedWarnings, files[0].Warnings)
+			}
+		})
+	}
+}
+
+func TestDetectWithoutFiles(t *testing.T) {
+	_, err := Detect(fstest.MapFS{"README.md": &fstest.MapFile{}})
+	if !errors.Is(err, ErrNoFiles) {
+		t.Errorf("expected ErrNoFiles, got %v", err)
+	}
+}
+
+func TestPoetryLockWithoutPyproject(t *testing.T) {
+	_, err := Detect(fstest.MapFS{"poetry.lock": &fstest.MapFile{Data: []byte("[[package]]\nname = \"requests\"\nversion = \"2.31.0\"\n")}})
+	if err == nil || !strings.Contains(err.Error(), "pyproject.toml required") {
+		t.Errorf("expected an error requiring pyproject.toml, got %v", err)
+	}
+}
+
+func TestPackageLockVersion1(t *testing.T) {
+	_, err := Detect(fstest.MapFS{"package-lock.json": &fstest.MapFile{Data: []byte(`{"lockfileVersion": 1}`)}})
+	if err == nil {
+		t.Errorf("expected an error for lockfileVersion 1")
+	}
+}
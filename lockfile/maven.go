@@ -0,0 +1,187 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"regexp"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+type pomProject struct {
+	GroupID    string        `xml:"groupId"`
+	Version    string        `xml:"version"`
+	Parent     pomParent     `xml:"parent"`
+	Properties pomProperties `xml:"properties"`
+	// DependencyManagement sets the versions and scopes of dependencies that do not set them
+	DependencyManagement pomDependencyManagement `xml:"dependencyManagement"`
+	// only direct children of <project>, so not the ones in <dependencyManagement>
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomDependencyManagement struct {
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomParent struct {
+	GroupID string `xml:"groupId"`
+	Version string `xml:"version"`
+}
+
+type pomProperties struct {
+	Entries []pomProperty `xml:",any"`
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Optional   bool   `xml:"optional"`
+}
+
+var pomPropertyReference = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// parsePOM reads the dependencies of a pom.xml file
+// except the ones in the test, provided and system scopes and optional ones.
+// Properties defined in the file are substituted,
+// and versions and scopes that are not set are taken from the <dependencyManagement> of the file;
+// dependencies whose version remains unknown (for instance because it is managed by a parent POM)
+// are ignored with a warning.
+func parsePOM(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	var project pomProject
+	err := xml.Unmarshal(content, &project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	properties := map[string]string{
+		"project.groupId":        project.GroupID,
+		"project.version":        project.Version,
+		"project.parent.groupId": project.Parent.GroupID,
+		"project.parent.version": project.Parent.Version,
+	}
+	if project.GroupID == "" {
+		properties["project.groupId"] = project.Parent.GroupID
+	}
+	if project.Version == "" {
+		properties["project.version"] = project.Parent.Version
+	}
+	for _, p := range project.Properties.Entries {
+		properties[p.XMLName.Local] = p.Value
+	}
+
+	resolve := func(s string) string {
+		// properties may reference other properties
+		for i := 0; i < 10 && pomPropertyReference.MatchString(s); i++ {
+			s = pomPropertyReference.ReplaceAllStringFunc(s, func(reference string) string {
+				if value, ok := properties[reference[2:len(reference)-1]]; ok {
+					return value
+				}
+				return reference
+			})
+		}
+		return s
+	}
+
+	managed := make(map[string]pomDependency)
+	for _, d := range project.DependencyManagement.Dependencies {
+		// imported BOMs only manage the versions of other dependencies
+		if d.Scope == "import" {
+			continue
+		}
+		managed[fmt.Sprintf("%s:%s", resolve(d.GroupID), resolve(d.ArtifactID))] = d
+	}
+
+	var deps []Dependency
+	var warnings []string
+	offset := dependenciesOffset(content)
+
+	for _, d := range project.Dependencies {
+		line := lineOf(content, "<artifactId>"+d.ArtifactID+"</artifactId>", offset)
+		if line > 0 {
+			offset = lineOffset(content, line)
+		}
+
+		depName := fmt.Sprintf("%s:%s", resolve(d.GroupID), resolve(d.ArtifactID))
+		if m, ok := managed[depName]; ok {
+			if d.Version == "" {
+				d.Version = m.Version
+			}
+			if d.Scope == "" {
+				d.Scope = m.Scope
+			}
+		}
+
+		switch d.Scope {
+		case "test", "provided", "system":
+			continue
+		}
+		if d.Optional {
+			continue
+		}
+
+		version := resolve(d.Version)
+		if version == "" {
+			warnings = append(warnings, fmt.Sprintf("ignoring %s (line %d): its version is not set in this file, for instance because it is managed by a parent POM", depName, line))
+			continue
+		}
+		if pomPropertyReference.MatchString(version) {
+			warnings = append(warnings, fmt.Sprintf("ignoring %s (line %d): its version %s is not defined in this file", depName, line, version))
+			continue
+		}
+
+		deps = append(deps, Dependency{
+			Package: aggregdepscore.Package{Name: depName, Version: version},
+			Line:    line,
+		})
+	}
+
+	return deps, warnings, nil
+}
+
+// dependenciesOffset returns the offset of the content of the <dependencies> element of the project,
+// so that the dependencies of <dependencyManagement> or of plugins are not mistaken for it
+func dependenciesOffset(content []byte) int {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			// depth 1 is <project>
+			if depth == 2 && t.Name.Local == "dependencies" {
+				return int(decoder.InputOffset())
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// lineOffset returns the offset of the start of the line after the given 1-based line
+func lineOffset(content []byte, line int) int {
+	for i, c := range content {
+		if c == '\n' {
+			line--
+			if line == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return len(content)
+}
@@ -0,0 +1,75 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+type packageLock struct {
+	LockfileVersion int                           `json:"lockfileVersion"`
+	Packages        map[string]packageLockPackage `json:"packages"`
+}
+
+type packageLockPackage struct {
+	Version              string            `json:"version"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// parsePackageLock reads the production dependencies of the root package
+// of a package-lock.json file (lockfileVersion 2 or 3);
+// development dependencies are ignored.
+func parsePackageLock(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	var lock packageLock
+	err := json.Unmarshal(content, &lock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lock.LockfileVersion < 2 {
+		return nil, nil, fmt.Errorf("unsupported lockfileVersion %d, regenerate it with npm 7 or later", lock.LockfileVersion)
+	}
+
+	root, ok := lock.Packages[""]
+	if !ok {
+		return nil, nil, fmt.Errorf("root package not found")
+	}
+
+	var names []string
+	for depName := range root.Dependencies {
+		names = append(names, depName)
+	}
+	for depName := range root.OptionalDependencies {
+		if _, ok := root.Dependencies[depName]; !ok {
+			names = append(names, depName)
+		}
+	}
+	sort.Strings(names)
+
+	var deps []Dependency
+	for _, depName := range names {
+		key := "node_modules/" + depName
+
+		installed, ok := lock.Packages[key]
+		if !ok {
+			// optional dependencies may not be installed
+			continue
+		}
+		if installed.Link {
+			// workspace package, not published
+			continue
+		}
+
+		deps = append(deps, Dependency{
+			Package: aggregdepscore.Package{Name: depName, Version: installed.Version},
+			Line:    lineOf(content, fmt.Sprintf("%q:", key), 0),
+		})
+	}
+
+	return deps, nil, nil
+}
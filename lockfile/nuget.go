@@ -0,0 +1,66 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+type nugetLock struct {
+	Dependencies map[string]map[string]nugetLockDependency `json:"dependencies"`
+}
+
+type nugetLockDependency struct {
+	Type     string `json:"type"`
+	Resolved string `json:"resolved"`
+}
+
+// parseNuGetLock reads the direct dependencies of a NuGet packages.lock.json file,
+// for all target frameworks
+func parseNuGetLock(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	var lock nugetLock
+	err := json.Unmarshal(content, &lock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[aggregdepscore.Package]bool)
+	var deps []Dependency
+
+	var frameworks []string
+	for framework := range lock.Dependencies {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Strings(frameworks)
+
+	for _, framework := range frameworks {
+		var names []string
+		for depName := range lock.Dependencies[framework] {
+			names = append(names, depName)
+		}
+		sort.Strings(names)
+
+		for _, depName := range names {
+			d := lock.Dependencies[framework][depName]
+			if d.Type != "Direct" {
+				continue
+			}
+
+			pkg := aggregdepscore.Package{Name: depName, Version: d.Resolved}
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+
+			deps = append(deps, Dependency{
+				Package: pkg,
+				Line:    lineOf(content, fmt.Sprintf("%q:", depName), 0),
+			})
+		}
+	}
+
+	return deps, nil, nil
+}
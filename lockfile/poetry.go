@@ -0,0 +1,94 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	aggregdepscore "github.com/DataDog/aggregated-dependency-score"
+)
+
+// parsePoetryLock reads the packages of a poetry.lock file
+// that are main dependencies in the pyproject.toml file next to it
+// (in [tool.poetry.dependencies] or in the dependencies of [project]).
+// poetry.lock does not tell direct dependencies apart, so pyproject.toml is required.
+func parsePoetryLock(fsys fs.FS, name string, content []byte) ([]Dependency, []string, error) {
+	tables, err := parseTOML(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direct, err := pyprojectDependencies(fsys, path.Join(path.Dir(name), "pyproject.toml"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var deps []Dependency
+	for _, t := range tables {
+		if t.header != "package" || !t.array {
+			continue
+		}
+
+		depName := t.values["name"].str
+		if !direct[normalizePythonName(depName)] {
+			continue
+		}
+
+		deps = append(deps, Dependency{
+			Package: aggregdepscore.Package{Name: depName, Version: t.values["version"].str},
+			Line:    t.values["name"].line,
+		})
+	}
+
+	return deps, nil, nil
+}
+
+// pyprojectDependencies returns the normalized names of the main dependencies
+// declared in a pyproject.toml file
+func pyprojectDependencies(fsys fs.FS, name string) (map[string]bool, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("pyproject.toml required next to poetry.lock to find the direct dependencies: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := parseTOML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool)
+	for _, t := range tables {
+		switch t.header {
+		case "tool.poetry.dependencies":
+			for key := range t.values {
+				if key != "python" {
+					result[normalizePythonName(key)] = true
+				}
+			}
+		case "project":
+			for _, requirement := range t.values["dependencies"].list {
+				if n := pythonRequirementName.FindString(requirement); n != "" {
+					result[normalizePythonName(n)] = true
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// pythonRequirementName matches the name at the start of a PEP 508 requirement
+var pythonRequirementName = regexp.MustCompile(`^\s*[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonName normalizes names as specified by PEP 503
+func normalizePythonName(name string) string {
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
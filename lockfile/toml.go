@@ -0,0 +1,225 @@
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tomlTable is a table of a TOML document, as read by parseTOML
+type tomlTable struct {
+	// header is the name of the table, empty for the top-level table
+	header string
+	// array tells if the table is an element of an array of tables ([[header]])
+	array  bool
+	line   int
+	values map[string]tomlValue
+}
+
+type tomlValue struct {
+	// str is set for strings
+	str string
+	// list holds the strings of arrays (other elements are ignored)
+	list []string
+	// raw is the raw text of the value
+	raw  string
+	line int
+}
+
+// parseTOML reads the subset of TOML used by Cargo.lock, poetry.lock and pyproject.toml:
+// tables, arrays of tables, and key/value pairs whose values are strings,
+// (possibly multi-line) arrays or anything else kept as raw text.
+// Dotted keys are not split.
+func parseTOML(content []byte) ([]*tomlTable, error) {
+	current := &tomlTable{values: make(map[string]tomlValue)}
+	tables := []*tomlTable{current}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			array := strings.HasPrefix(line, "[[")
+			header := strings.Trim(line, "[]")
+			current = &tomlTable{header: strings.TrimSpace(header), array: array, line: lineNumber, values: make(map[string]tomlValue)}
+			tables = append(tables, current)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key = unquoteTOMLKey(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		start := lineNumber
+
+		// multi-line strings and arrays
+		for (strings.HasPrefix(value, `"""`) && (len(value) < 6 || !strings.HasSuffix(value, `"""`))) ||
+			(strings.HasPrefix(value, "[") && !tomlBracketsBalanced(value)) {
+			if !scanner.Scan() {
+				return nil, fmt.Errorf("line %d: unterminated value", start)
+			}
+			lineNumber++
+			next := scanner.Text()
+			if !strings.HasPrefix(value, `"""`) {
+				next = stripTOMLComment(next)
+			}
+			value += "\n" + next
+		}
+
+		v := tomlValue{raw: value, line: start}
+		switch {
+		case strings.HasPrefix(value, `"""`):
+			v.str = strings.TrimPrefix(strings.TrimSuffix(value, `"""`), `"""`)
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+			s, err := unquoteTOMLString(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", start, err)
+			}
+			v.str = s
+		case strings.HasPrefix(value, "["):
+			list, err := tomlArrayStrings(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", start, err)
+			}
+			v.list = list
+		}
+
+		current.values[key] = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// stripTOMLComment removes a trailing comment, taking strings into account
+func stripTOMLComment(line string) string {
+	var quote rune
+	escaped := false
+
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+func tomlBracketsBalanced(s string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+
+	for _, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+
+	return depth == 0
+}
+
+// tomlArrayStrings returns the strings that are direct elements of an array
+func tomlArrayStrings(s string) ([]string, error) {
+	var result []string
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(s) && s[end] != c {
+				if c == '"' && s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+
+			if depth == 1 {
+				str, err := unquoteTOMLString(s[i : end+1])
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, str)
+			}
+			i = end
+		}
+	}
+
+	return result, nil
+}
+
+func unquoteTOMLString(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], nil
+	}
+
+	// TOML basic strings are close enough to Go string literals
+	end := 1
+	for end < len(s) && s[end] != '"' {
+		if s[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(s) {
+		return "", fmt.Errorf("unterminated string")
+	}
+
+	return strconv.Unquote(s[:end+1])
+}
+
+func unquoteTOMLKey(key string) string {
+	if s, err := unquoteTOMLString(key); err == nil && (strings.HasPrefix(key, `"`) || strings.HasPrefix(key, "'")) {
+		return s
+	}
+
+	return key
+}
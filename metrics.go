@@ -0,0 +1,153 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "deps.dev/api/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const metricsNamespace = "aggregdepscore"
+
+// Metrics holds the Prometheus collectors of the evaluator and of the deps.dev client.
+// The same Metrics can be given to both with WithMetrics and WithDepsDotDevMetrics.
+type Metrics struct {
+	rpcRequests       *prometheus.CounterVec
+	rpcDuration       *prometheus.HistogramVec
+	cacheRequests     *prometheus.CounterVec
+	packagesEvaluated prometheus.Counter
+	cyclesSkipped     prometheus.Counter
+	fallbacksApplied  prometheus.Counter
+	scores            prometheus.Histogram
+}
+
+// NewMetrics creates the collectors and registers them on registerer.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "depsdotdev_requests_total",
+			Help:      "Number of deps.dev RPCs by method and gRPC status code.",
+		}, []string{"method", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "depsdotdev_request_duration_seconds",
+			Help:      "Latency of deps.dev RPCs by method and gRPC status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cache_requests_total",
+			Help:      "Number of cache lookups by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		packagesEvaluated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "packages_evaluated_total",
+			Help:      "Number of packages (nodes of dependency graphs) evaluated.",
+		}),
+		cyclesSkipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cycles_skipped_total",
+			Help:      "Number of dependency cycles that were ignored.",
+		}),
+		fallbacksApplied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "fallbacks_applied_total",
+			Help:      "Number of packages for which an intrinsic trustworthiness was assumed.",
+		}),
+		scores: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "score",
+			Help:      "Distribution of the aggregated dependency scores of evaluated packages.",
+			Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.rpcRequests,
+		m.rpcDuration,
+		m.cacheRequests,
+		m.packagesEvaluated,
+		m.cyclesSkipped,
+		m.fallbacksApplied,
+		m.scores,
+	} {
+		err := registerer.Register(c)
+		if err != nil {
+			return nil, fmt.Errorf("registering collector: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Metrics) cacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// metricsObserver updates the evaluator metrics
+type metricsObserver struct {
+	NopObserver
+	metrics *Metrics
+}
+
+func (o *metricsObserver) NodeFinished(ctx context.Context, event NodeFinishedEvent) {
+	o.metrics.packagesEvaluated.Inc()
+}
+
+func (o *metricsObserver) CycleSkipped(ctx context.Context, cycle Cycle) {
+	o.metrics.cyclesSkipped.Inc()
+}
+
+func (o *metricsObserver) FallbackApplied(ctx context.Context, fallback Fallback) {
+	o.metrics.fallbacksApplied.Inc()
+}
+
+// meteredInsightsClient counts and times deps.dev RPCs
+type meteredInsightsClient struct {
+	api.InsightsClient
+	metrics *Metrics
+}
+
+func (c *meteredInsightsClient) observe(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	c.metrics.rpcRequests.WithLabelValues(method, code).Inc()
+	c.metrics.rpcDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+func (c *meteredInsightsClient) GetVersion(ctx context.Context, in *api.GetVersionRequest, opts ...grpc.CallOption) (*api.Version, error) {
+	start := time.Now()
+	result, err := c.InsightsClient.GetVersion(ctx, in, opts...)
+	c.observe("GetVersion", start, err)
+	return result, err
+}
+
+func (c *meteredInsightsClient) GetRequirements(ctx context.Context, in *api.GetRequirementsRequest, opts ...grpc.CallOption) (*api.Requirements, error) {
+	start := time.Now()
+	result, err := c.InsightsClient.GetRequirements(ctx, in, opts...)
+	c.observe("GetRequirements", start, err)
+	return result, err
+}
+
+func (c *meteredInsightsClient) GetDependencies(ctx context.Context, in *api.GetDependenciesRequest, opts ...grpc.CallOption) (*api.Dependencies, error) {
+	start := time.Now()
+	result, err := c.InsightsClient.GetDependencies(ctx, in, opts...)
+	c.observe("GetDependencies", start, err)
+	return result, err
+}
+
+func (c *meteredInsightsClient) GetProject(ctx context.Context, in *api.GetProjectRequest, opts ...grpc.CallOption) (*api.Project, error) {
+	start := time.Now()
+	result, err := c.InsightsClient.GetProject(ctx, in, opts...)
+	c.observe("GetProject", start, err)
+	return result, err
+}
@@ -0,0 +1,78 @@
+package aggregdepscore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	api "deps.dev/api/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	metrics, err := NewMetrics(registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/babel/babel", 7)
+	fake.AddVersion(api.System_NPM, "@babel/core", "7.0.0", []string{"github.com/babel/babel"},
+		&api.VersionKey{System: api.System_NPM, Name: "@babel/parser", Version: "7.0.0"},
+		&api.VersionKey{System: api.System_NPM, Name: "unknown", Version: "1.0.0"},
+	)
+	fake.AddVersion(api.System_NPM, "@babel/parser", "7.0.0", []string{"github.com/babel/babel"})
+	fake.AddVersion(api.System_NPM, "unknown", "1.0.0", nil)
+
+	depsdotdev, err := NewDepsDotDevClient(WithInsightsClient(fake), WithDepsDotDevMetrics(metrics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluator, err := NewEvaluator(depsdotdev, depsdotdev, WithMetrics(metrics), WithIntrinsicFallback(0.9))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.EvaluateScore(context.Background(), Package{Ecosystem: "npm", Name: "@babel/core", Version: "7.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+# HELP aggregdepscore_cache_requests_total Number of cache lookups by cache and result (hit or miss).
+# TYPE aggregdepscore_cache_requests_total counter
+aggregdepscore_cache_requests_total{cache="depsdotdev_project",result="hit"} 1
+aggregdepscore_cache_requests_total{cache="depsdotdev_project",result="miss"} 1
+# HELP aggregdepscore_depsdotdev_requests_total Number of deps.dev RPCs by method and gRPC status code.
+# TYPE aggregdepscore_depsdotdev_requests_total counter
+aggregdepscore_depsdotdev_requests_total{code="OK",method="GetDependencies"} 3
+aggregdepscore_depsdotdev_requests_total{code="OK",method="GetProject"} 1
+aggregdepscore_depsdotdev_requests_total{code="OK",method="GetVersion"} 3
+# HELP aggregdepscore_fallbacks_applied_total Number of packages for which an intrinsic trustworthiness was assumed.
+# TYPE aggregdepscore_fallbacks_applied_total counter
+aggregdepscore_fallbacks_applied_total 1
+# HELP aggregdepscore_packages_evaluated_total Number of packages (nodes of dependency graphs) evaluated.
+# TYPE aggregdepscore_packages_evaluated_total counter
+aggregdepscore_packages_evaluated_total 3
+`
+
+	err = testutil.CollectAndCompare(registry, strings.NewReader(expected),
+		"aggregdepscore_cache_requests_total",
+		"aggregdepscore_depsdotdev_requests_total",
+		"aggregdepscore_fallbacks_applied_total",
+		"aggregdepscore_packages_evaluated_total",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if count := testutil.CollectAndCount(registry, "aggregdepscore_score"); count != 1 {
+		t.Errorf("expected the score histogram to be collected, got %d metrics", count)
+	}
+}
@@ -0,0 +1,111 @@
+package aggregdepscore
+
+import (
+	"context"
+	"time"
+)
+
+// Observer is notified of the progress of evaluations,
+// for instance to display a progress bar, write an audit log or record metrics.
+//
+// Methods are called synchronously during the evaluation so they should return quickly;
+// they must be safe for concurrent use.
+// Embed NopObserver to implement only some of the methods.
+type Observer interface {
+	NodeStarted(ctx context.Context, event NodeStartedEvent)
+	NodeFinished(ctx context.Context, event NodeFinishedEvent)
+	CycleSkipped(ctx context.Context, cycle Cycle)
+	FallbackApplied(ctx context.Context, fallback Fallback)
+	Error(ctx context.Context, event ErrorEvent)
+}
+
+// NodeStartedEvent is sent when the evaluation of a package starts.
+type NodeStartedEvent struct {
+	Package Package
+	// Depth is 0 for the package being evaluated,
+	// 1 for its direct dependencies, etc.
+	Depth int
+}
+
+// NodeFinishedEvent is sent when the evaluation of a package succeeded.
+type NodeFinishedEvent struct {
+	Package                   Package
+	Depth                     int
+	IntrinsicTrustworthiness  float64
+	AggregatedTrustworthiness float64
+	// Duration includes the evaluation of the dependencies of the package
+	Duration time.Duration
+}
+
+// ErrorSource tells which component returned an error.
+type ErrorSource string
+
+const (
+	ErrorSourceIntrinsicEvaluator ErrorSource = "intrinsic_evaluator"
+	ErrorSourceDependencyResolver ErrorSource = "dependency_resolver"
+)
+
+// ErrorEvent is sent when the intrinsic trustworthiness evaluator
+// or the dependency resolver returns an error.
+// The error may be recovered from if a fallback applies.
+type ErrorEvent struct {
+	Package Package
+	Depth   int
+	Source  ErrorSource
+	Err     error
+}
+
+// Fallback records that a value was assumed for a package
+// instead of being evaluated.
+type Fallback struct {
+	Package Package
+	// IntrinsicTrustworthiness is the value that was assumed
+	IntrinsicTrustworthiness float64
+	Reason                   string
+}
+
+// NopObserver is an Observer that does nothing.
+type NopObserver struct{}
+
+// compile-time interface checks
+var _ Observer = NopObserver{}
+var _ Observer = multiObserver{}
+
+func (NopObserver) NodeStarted(ctx context.Context, event NodeStartedEvent)   {}
+func (NopObserver) NodeFinished(ctx context.Context, event NodeFinishedEvent) {}
+func (NopObserver) CycleSkipped(ctx context.Context, cycle Cycle)             {}
+func (NopObserver) FallbackApplied(ctx context.Context, fallback Fallback)    {}
+func (NopObserver) Error(ctx context.Context, event ErrorEvent)               {}
+
+// multiObserver forwards events to several observers
+type multiObserver []Observer
+
+func (m multiObserver) NodeStarted(ctx context.Context, event NodeStartedEvent) {
+	for _, o := range m {
+		o.NodeStarted(ctx, event)
+	}
+}
+
+func (m multiObserver) NodeFinished(ctx context.Context, event NodeFinishedEvent) {
+	for _, o := range m {
+		o.NodeFinished(ctx, event)
+	}
+}
+
+func (m multiObserver) CycleSkipped(ctx context.Context, cycle Cycle) {
+	for _, o := range m {
+		o.CycleSkipped(ctx, cycle)
+	}
+}
+
+func (m multiObserver) FallbackApplied(ctx context.Context, fallback Fallback) {
+	for _, o := range m {
+		o.FallbackApplied(ctx, fallback)
+	}
+}
+
+func (m multiObserver) Error(ctx context.Context, event ErrorEvent) {
+	for _, o := range m {
+		o.Error(ctx, event)
+	}
+}
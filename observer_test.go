@@ -0,0 +1,135 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type recordingObserver struct {
+	NopObserver
+
+	mutex     sync.Mutex
+	events    []string
+	finished  map[string]NodeFinishedEvent
+	fallbacks []Fallback
+}
+
+func (o *recordingObserver) record(format string, args ...any) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *recordingObserver) NodeStarted(ctx context.Context, event NodeStartedEvent) {
+	o.record("start %s %d", event.Package.Name, event.Depth)
+}
+
+func (o *recordingObserver) NodeFinished(ctx context.Context, event NodeFinishedEvent) {
+	o.record("finish %s", event.Package.Name)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.finished == nil {
+		o.finished = make(map[string]NodeFinishedEvent)
+	}
+	o.finished[event.Package.Name] = event
+}
+
+func (o *recordingObserver) CycleSkipped(ctx context.Context, cycle Cycle) {
+	o.record("cycle %d", len(cycle.Packages))
+}
+
+func (o *recordingObserver) FallbackApplied(ctx context.Context, fallback Fallback) {
+	o.record("fallback %s", fallback.Package.Name)
+}
+
+func (o *recordingObserver) Error(ctx context.Context, event ErrorEvent) {
+	o.record("error %s %s", event.Package.Name, event.Source)
+}
+
+func TestObserver(t *testing.T) {
+	observer := &recordingObserver{}
+
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{
+				"A": 0.92,
+				"B": 0.94,
+				// C is unknown and will fall back
+			},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{
+				"A": {"B", "C"},
+				"B": {"A"},
+				"C": {},
+			},
+		},
+		WithObserver(observer),
+		WithIntrinsicFallback(0.85),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"start A 0",
+		"start B 1",
+		"cycle 3",
+		"finish B",
+		"start C 1",
+		"error C intrinsic_evaluator",
+		"fallback C",
+		"finish C",
+		"finish A",
+	}
+
+	if fmt.Sprint(observer.events) != fmt.Sprint(expected) {
+		t.Fatalf("expected events %q, got %q", expected, observer.events)
+	}
+
+	if observer.finished["C"].IntrinsicTrustworthiness != 0.85 {
+		t.Errorf("expected fallback value for C, got %+v", observer.finished["C"])
+	}
+
+	if observer.finished["A"].AggregatedTrustworthiness != evaluation.Trustworthiness {
+		t.Errorf("aggregated trustworthiness of root does not match the evaluation: %+v", observer.finished["A"])
+	}
+
+	if len(evaluation.Fallbacks) != 1 || evaluation.Fallbacks[0].Package.Name != "C" {
+		t.Errorf("expected one fallback for C, got %+v", evaluation.Fallbacks)
+	}
+}
+
+func TestNoFallbackByDefault(t *testing.T) {
+	observer := &recordingObserver{}
+
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{},
+		&testDependencyResolver{},
+		WithObserver(observer),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	expected := []string{"start A 0", "error A intrinsic_evaluator"}
+
+	if fmt.Sprint(observer.events) != fmt.Sprint(expected) {
+		t.Fatalf("expected events %q, got %q", expected, observer.events)
+	}
+}
@@ -0,0 +1,344 @@
+package aggregdepscore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// RepositoryResolver finds the source repository of a package,
+// for instance "github.com/DataDog/aggregated-dependency-score".
+type RepositoryResolver interface {
+	GetRepository(ctx context.Context, p Package) (string, error)
+}
+
+// Overrides is a list of rules that pin the intrinsic trustworthiness
+// of some packages, typically first-party packages
+// or packages that were audited by a security team.
+//
+// Rules are tried in order and the first matching rule wins.
+type Overrides struct {
+	Rules []OverrideRule `json:"overrides"`
+}
+
+// OverrideRule matches packages using exactly one of
+// Name, NameGlob, Repository or Organization.
+// Ecosystem and Version further restrict the match when they are set.
+type OverrideRule struct {
+	Ecosystem string `json:"ecosystem,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Version   string `json:"version,omitempty"`
+	// NameGlob uses the syntax of path.Match,
+	// for instance "@aws-sdk/*"
+	NameGlob string `json:"name_glob,omitempty"`
+	// Repository is the source repository of the package,
+	// for instance "github.com/DataDog/dd-trace-go"
+	Repository string `json:"repository,omitempty"`
+	// Organization is the owner of the source repository of the package,
+	// for instance "github.com/DataDog"
+	Organization string `json:"organization,omitempty"`
+
+	// Trustworthiness replaces the intrinsic trustworthiness of matched packages;
+	// if nil, the intrinsic trustworthiness is left unchanged.
+	// It must be greater than 0 (the logarithm of 0 is infinite) and at most 1;
+	// use a tiny value to distrust a package entirely.
+	Trustworthiness *float64 `json:"trustworthiness,omitempty"`
+	// SkipDependencies stops the evaluation from descending
+	// into the dependencies of matched packages,
+	// for instance because the whole subtree was vetted.
+	SkipDependencies bool `json:"skip_dependencies,omitempty"`
+	// Reason is free text for humans, it is not used by the algorithm
+	Reason string `json:"reason,omitempty"`
+}
+
+// LoadOverrides reads overrides from a JSON file
+// of the form {"overrides": [{"name_glob": "@acme/*", "trustworthiness": 1}]}.
+func LoadOverrides(filename string) (*Overrides, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading overrides file: %w", err)
+	}
+
+	var overrides Overrides
+
+	err = json.Unmarshal(content, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("parsing overrides file: %w", err)
+	}
+
+	err = overrides.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating overrides file: %w", err)
+	}
+
+	return &overrides, nil
+}
+
+// Validate checks that every rule is well-formed.
+func (o *Overrides) Validate() error {
+	for i, rule := range o.Rules {
+		err := rule.validate()
+		if err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *Overrides) needsRepository() bool {
+	for _, rule := range o.Rules {
+		if rule.Repository != "" || rule.Organization != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule *OverrideRule) validate() error {
+	nbSelectors := 0
+	for _, selector := range []string{rule.Name, rule.NameGlob, rule.Repository, rule.Organization} {
+		if selector != "" {
+			nbSelectors++
+		}
+	}
+
+	if nbSelectors != 1 {
+		return errors.New("exactly one of name, name_glob, repository and organization must be set")
+	}
+
+	if rule.NameGlob != "" {
+		// path.Match only reports malformed patterns when trying to match
+		_, err := path.Match(rule.NameGlob, "")
+		if err != nil {
+			return fmt.Errorf("invalid name_glob %q: %w", rule.NameGlob, err)
+		}
+	}
+
+	if rule.Trustworthiness == nil && !rule.SkipDependencies {
+		return errors.New("rule has no effect: set trustworthiness and/or skip_dependencies")
+	}
+
+	if rule.Trustworthiness != nil && !(*rule.Trustworthiness > 0 && *rule.Trustworthiness <= 1) {
+		return fmt.Errorf("trustworthiness must be greater than 0 and at most 1, got %g", *rule.Trustworthiness)
+	}
+
+	return nil
+}
+
+// matchesWithoutRepository tells if the rule matches the package
+// for rules that do not need the source repository of the package.
+func (rule *OverrideRule) matchesWithoutRepository(p Package) bool {
+	if rule.Ecosystem != "" && rule.Ecosystem != p.Ecosystem {
+		return false
+	}
+
+	if rule.Version != "" && rule.Version != p.Version {
+		return false
+	}
+
+	if rule.Name != "" {
+		return rule.Name == p.Name
+	}
+
+	if rule.NameGlob != "" {
+		// the pattern was validated in validate()
+		matched, _ := path.Match(rule.NameGlob, p.Name)
+		return matched
+	}
+
+	return false
+}
+
+func (rule *OverrideRule) matchesRepository(repository string) bool {
+	repository = normalizeRepository(repository)
+
+	if rule.Repository != "" {
+		return normalizeRepository(rule.Repository) == repository
+	}
+
+	if rule.Organization != "" {
+		return strings.HasPrefix(repository, normalizeRepository(rule.Organization)+"/")
+	}
+
+	return false
+}
+
+func normalizeRepository(repository string) string {
+	repository = strings.ToLower(repository)
+	repository = strings.TrimPrefix(repository, "https://")
+	repository = strings.TrimPrefix(repository, "http://")
+	repository = strings.TrimSuffix(repository, "/")
+	repository = strings.TrimSuffix(repository, ".git")
+
+	return repository
+}
+
+// OverrideLayer applies Overrides on top of any intrinsic trustworthiness evaluator
+// and dependency resolver.
+// It satisfies both the IntrinsicTrustworthinessEvaluator and DependencyResolver interfaces.
+type OverrideLayer struct {
+	overrides    *Overrides
+	intrinsic    IntrinsicTrustworthinessEvaluator
+	deps         DependencyResolver
+	repositories RepositoryResolver
+
+	repositoryCacheMutex sync.Mutex
+	repositoryCache      map[Package]string
+}
+
+// compile-time interface checks
+var _ IntrinsicTrustworthinessEvaluator = &OverrideLayer{}
+var _ DependencyResolver = &OverrideLayer{}
+var _ RepositoryResolver = &OverrideLayer{}
+
+// NewOverrideLayer wraps intrinsic and deps so that overrides are applied.
+//
+// Rules using a repository or an organization need to know the source repository of packages:
+// it is obtained from intrinsic or deps if one of them implements RepositoryResolver
+// (which is the case of the deps.dev client).
+func NewOverrideLayer(overrides *Overrides, intrinsic IntrinsicTrustworthinessEvaluator, deps DependencyResolver) (*OverrideLayer, error) {
+	if overrides == nil {
+		return nil, errors.New("overrides are required")
+	}
+
+	if intrinsic == nil {
+		return nil, errors.New("intrinsic trustworthiness evaluator is required")
+	}
+
+	if deps == nil {
+		return nil, errors.New("dependency resolver is required")
+	}
+
+	err := overrides.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating overrides: %w", err)
+	}
+
+	layer := &OverrideLayer{
+		overrides:       overrides,
+		intrinsic:       intrinsic,
+		deps:            deps,
+		repositoryCache: make(map[Package]string),
+	}
+
+	if r, ok := intrinsic.(RepositoryResolver); ok {
+		layer.repositories = r
+	} else if r, ok := deps.(RepositoryResolver); ok {
+		layer.repositories = r
+	}
+
+	if layer.repositories == nil && overrides.needsRepository() {
+		return nil, errors.New("overrides match on repository or organization but neither the intrinsic evaluator nor the dependency resolver implements RepositoryResolver")
+	}
+
+	return layer, nil
+}
+
+// Match returns the first rule matching the package, or nil if there is none.
+// Packages whose source repository cannot be found match no repository or organization rule.
+func (l *OverrideLayer) Match(ctx context.Context, p Package) (*OverrideRule, error) {
+	var repository string
+	repositoryFetched := false
+
+	for i := range l.overrides.Rules {
+		rule := &l.overrides.Rules[i]
+
+		if rule.Repository == "" && rule.Organization == "" {
+			if rule.matchesWithoutRepository(p) {
+				return rule, nil
+			}
+			continue
+		}
+
+		if rule.Ecosystem != "" && rule.Ecosystem != p.Ecosystem {
+			continue
+		}
+
+		if rule.Version != "" && rule.Version != p.Version {
+			continue
+		}
+
+		if !repositoryFetched {
+			var err error
+			repository, err = l.getRepository(ctx, p)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, fmt.Errorf("getting repository of package: %w", err)
+				}
+				// a package without a known source repository matches no repository or organization rule,
+				// but can still match the following rules
+				repository = ""
+			}
+			repositoryFetched = true
+		}
+
+		if repository != "" && rule.matchesRepository(repository) {
+			return rule, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (l *OverrideLayer) getRepository(ctx context.Context, p Package) (string, error) {
+	l.repositoryCacheMutex.Lock()
+	repository, ok := l.repositoryCache[p]
+	l.repositoryCacheMutex.Unlock()
+
+	if ok {
+		return repository, nil
+	}
+
+	repository, err := l.repositories.GetRepository(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	l.repositoryCacheMutex.Lock()
+	l.repositoryCache[p] = repository
+	l.repositoryCacheMutex.Unlock()
+
+	return repository, nil
+}
+
+// GetRepository forwards to the wrapped RepositoryResolver, if any.
+func (l *OverrideLayer) GetRepository(ctx context.Context, p Package) (string, error) {
+	if l.repositories == nil {
+		return "", errors.New("no repository resolver available")
+	}
+
+	return l.getRepository(ctx, p)
+}
+
+func (l *OverrideLayer) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
+	rule, err := l.Match(ctx, p)
+	if err != nil {
+		return 0, fmt.Errorf("matching overrides: %w", err)
+	}
+
+	if rule != nil && rule.Trustworthiness != nil {
+		return *rule.Trustworthiness, nil
+	}
+
+	return l.intrinsic.EvaluateIntrinsicTrustworthiness(ctx, p)
+}
+
+func (l *OverrideLayer) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	rule, err := l.Match(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("matching overrides: %w", err)
+	}
+
+	if rule != nil && rule.SkipDependencies {
+		return nil, nil
+	}
+
+	return l.deps.GetDirectDependencies(ctx, p)
+}
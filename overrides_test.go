@@ -0,0 +1,201 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testRepositoryResolver struct {
+	repositoryByName map[string]string
+}
+
+func (r *testRepositoryResolver) GetRepository(ctx context.Context, p Package) (string, error) {
+	repository, ok := r.repositoryByName[p.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown package %q", p.Name)
+	}
+
+	return repository, nil
+}
+
+// testRepositoryAwareEvaluator is a testIntrinsicTrustworthinessEvaluator
+// that also implements RepositoryResolver
+type testRepositoryAwareEvaluator struct {
+	testIntrinsicTrustworthinessEvaluator
+	testRepositoryResolver
+}
+
+func TestOverrideMatching(t *testing.T) {
+	one := 1.0
+
+	overrides := &Overrides{Rules: []OverrideRule{
+		{Ecosystem: "npm", Name: "exact", Version: "1.0.0", Trustworthiness: &one},
+		{Ecosystem: "npm", NameGlob: "@acme/*", Trustworthiness: &one},
+		{Repository: "https://github.com/Vetted/Repo.git", Trustworthiness: &one},
+		{Organization: "github.com/acme", Trustworthiness: &one},
+		{Ecosystem: "go", Name: "no-repository", Trustworthiness: &one},
+	}}
+
+	repositories := testRepositoryResolver{repositoryByName: map[string]string{
+		"exact":           "github.com/someone/exact",
+		"other":           "github.com/someone/other",
+		"vetted":          "github.com/vetted/repo",
+		"first-party":     "github.com/acme/first-party",
+		"lookalike":       "github.com/acme-evil/lookalike",
+		"@acme/client-s3": "github.com/someone/client-s3",
+	}}
+
+	layer, err := NewOverrideLayer(overrides, &testRepositoryAwareEvaluator{testRepositoryResolver: repositories}, &testDependencyResolver{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, each := range []struct {
+		name     string
+		pkg      Package
+		expected int // index of the expected rule, -1 if none
+	}{
+		{"exact package", Package{Ecosystem: "npm", Name: "exact", Version: "1.0.0"}, 0},
+		{"exact package, other version", Package{Ecosystem: "npm", Name: "exact", Version: "2.0.0"}, -1},
+		{"glob", Package{Ecosystem: "npm", Name: "@acme/client-s3", Version: "1.0.0"}, 1},
+		{"glob, other ecosystem", Package{Ecosystem: "pypi", Name: "@acme/client-s3", Version: "1.0.0"}, -1},
+		{"repository", Package{Ecosystem: "go", Name: "vetted", Version: "v1.0.0"}, 2},
+		{"organization", Package{Ecosystem: "go", Name: "first-party", Version: "v1.0.0"}, 3},
+		{"organization prefix is not a match", Package{Ecosystem: "go", Name: "lookalike", Version: "v1.0.0"}, -1},
+		{"no match", Package{Ecosystem: "go", Name: "other", Version: "v1.0.0"}, -1},
+		{"unknown repository", Package{Ecosystem: "go", Name: "unknown", Version: "v1.0.0"}, -1},
+		{"unknown repository, name rule after repository rules", Package{Ecosystem: "go", Name: "no-repository", Version: "v1.0.0"}, 4},
+	} {
+		t.Run(each.name, func(t *testing.T) {
+			rule, err := layer.Match(context.Background(), each.pkg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if each.expected == -1 {
+				if rule != nil {
+					t.Fatalf("expected no match, got %+v", *rule)
+				}
+				return
+			}
+
+			if rule != &overrides.Rules[each.expected] {
+				t.Fatalf("expected rule %d, got %+v", each.expected, rule)
+			}
+		})
+	}
+}
+
+func TestOverrideLayerEvaluation(t *testing.T) {
+	vetted := 0.99
+
+	intrinsic := &testIntrinsicTrustworthinessEvaluator{
+		trustworthinessByName: map[string]float64{
+			"A": 0.92,
+			"B": 0.94,
+			"C": 0.93,
+			"D": 0.84,
+		},
+	}
+
+	deps := &testDependencyResolver{
+		directDependencyNamesByName: map[string][]string{
+			"A": {"B", "C"},
+			"B": {"D"},
+			"C": {},
+			"D": {},
+		},
+	}
+
+	layer, err := NewOverrideLayer(&Overrides{Rules: []OverrideRule{
+		{Name: "B", Trustworthiness: &vetted, SkipDependencies: true},
+	}}, intrinsic, deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eval := trustwhorthinessEvaluator{intrinsic: layer, deps: layer}
+
+	tPrimeA, err := eval.evaluate(context.Background(), &evaluationState{}, Package{Name: "A"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// D is not evaluated at all because B's subtree is vetted
+	expected := 0.92 * math.Pow(0.99, 1.5) * math.Pow(0.93, 1.5)
+	allowedError := 1e-10
+
+	if math.Abs(tPrimeA-expected) > allowedError {
+		t.Fatalf("expected %g, got %g", expected, tPrimeA)
+	}
+
+	if intrinsic.nbQueryByPackage["B"] != 0 {
+		t.Fatalf("intrinsic trustworthiness of B should not have been queried")
+	}
+
+	if intrinsic.nbQueryByPackage["D"] != 0 {
+		t.Fatalf("package D should not have been evaluated")
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	err := os.WriteFile(valid, []byte(`{
+		"overrides": [
+			{"ecosystem": "npm", "name_glob": "@acme/*", "trustworthiness": 1, "reason": "first-party"},
+			{"organization": "github.com/acme", "skip_dependencies": true}
+		]
+	}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadOverrides(valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(overrides.Rules) != 2 || !overrides.Rules[1].SkipDependencies {
+		t.Fatalf("unexpected overrides: %+v", overrides)
+	}
+
+	for name, content := range map[string]string{
+		"two selectors":         `{"overrides": [{"name": "a", "name_glob": "b*", "trustworthiness": 1}]}`,
+		"no selector":           `{"overrides": [{"trustworthiness": 1}]}`,
+		"no effect":             `{"overrides": [{"name": "a"}]}`,
+		"out of range":          `{"overrides": [{"name": "a", "trustworthiness": 1.5}]}`,
+		"zero trustworthiness":  `{"overrides": [{"name": "a", "trustworthiness": 0}]}`,
+		"malformed glob":        `{"overrides": [{"name_glob": "[", "trustworthiness": 1}]}`,
+		"not an overrides file": `[]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, "invalid.json")
+			err := os.WriteFile(filename, []byte(content), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = LoadOverrides(filename)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestOverrideLayerRequiresRepositoryResolver(t *testing.T) {
+	one := 1.0
+
+	_, err := NewOverrideLayer(&Overrides{Rules: []OverrideRule{
+		{Organization: "github.com/acme", Trustworthiness: &one},
+	}}, &testIntrinsicTrustworthinessEvaluator{}, &testDependencyResolver{})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -0,0 +1,59 @@
+package aggregdepscore
+
+import "context"
+
+// ProjectEcosystem is the ecosystem of the synthetic root packages of EvaluateProject.
+const ProjectEcosystem = "project"
+
+// EvaluateProject evaluates a project that is not a published package,
+// for instance an application, given its direct dependencies
+// (see the lockfile package to read them from manifests and lockfiles).
+//
+// The project is evaluated as a synthetic root package
+// whose intrinsic trustworthiness is 1, as the code of the project itself is not assessed,
+// and whose direct dependencies are directDependencies.
+// Per-dependency results are in Evaluation.DirectDependencies.
+func (e *Evaluator) EvaluateProject(ctx context.Context, name string, directDependencies []Package) (*Evaluation, error) {
+	root := Package{Ecosystem: ProjectEcosystem, Name: name}
+
+	trustworthiness := e.trustworthiness
+	layer := &syntheticRoot{
+		root:         root,
+		dependencies: directDependencies,
+		intrinsic:    trustworthiness.intrinsic,
+		deps:         trustworthiness.deps,
+	}
+	trustworthiness.intrinsic = layer
+	trustworthiness.deps = layer
+
+	return e.evaluate(ctx, &trustworthiness, root)
+}
+
+// syntheticRoot adds a root package with the given dependencies
+// on top of an intrinsic trustworthiness evaluator and a dependency resolver
+type syntheticRoot struct {
+	root         Package
+	dependencies []Package
+	intrinsic    IntrinsicTrustworthinessEvaluator
+	deps         DependencyResolver
+}
+
+// compile-time interface checks
+var _ IntrinsicTrustworthinessEvaluator = &syntheticRoot{}
+var _ DependencyResolver = &syntheticRoot{}
+
+func (r *syntheticRoot) EvaluateIntrinsicTrustworthiness(ctx context.Context, p Package) (float64, error) {
+	if p == r.root {
+		return 1.0, nil
+	}
+
+	return r.intrinsic.EvaluateIntrinsicTrustworthiness(ctx, p)
+}
+
+func (r *syntheticRoot) GetDirectDependencies(ctx context.Context, p Package) ([]Package, error) {
+	if p == r.root {
+		return r.dependencies, nil
+	}
+
+	return r.deps.GetDirectDependencies(ctx, p)
+}
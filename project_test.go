@@ -0,0 +1,51 @@
+package aggregdepscore
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestEvaluateProject(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.95, "C": 0.8},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"C"}, "B": {}, "C": {}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.EvaluateProject(context.Background(), "my-app", []Package{{Name: "A"}, {Name: "B"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluation.Package != (Package{Ecosystem: ProjectEcosystem, Name: "my-app"}) {
+		t.Errorf("unexpected root package: %+v", evaluation.Package)
+	}
+
+	tPrimeA := 0.9 * math.Pow(0.8, 1.5)
+	tPrimeB := 0.95
+	expected := math.Pow(tPrimeA, 1.5) * math.Pow(tPrimeB, 1.5)
+	if math.Abs(evaluation.Trustworthiness-expected) > 1e-12 {
+		t.Errorf("expected trustworthiness %f, got %f", expected, evaluation.Trustworthiness)
+	}
+
+	if evaluation.Components.Intrinsic != 1 {
+		t.Errorf("expected the synthetic root to have an intrinsic trustworthiness of 1, got %f", evaluation.Components.Intrinsic)
+	}
+
+	if len(evaluation.DirectDependencies) != 2 {
+		t.Fatalf("expected 2 direct dependencies, got %+v", evaluation.DirectDependencies)
+	}
+
+	for i, expected := range []float64{tPrimeA, tPrimeB} {
+		if math.Abs(evaluation.DirectDependencies[i].AggregatedTrustworthiness-expected) > 1e-12 {
+			t.Errorf("dependency %d: expected %f, got %f", i, expected, evaluation.DirectDependencies[i].AggregatedTrustworthiness)
+		}
+	}
+}
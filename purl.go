@@ -0,0 +1,95 @@
+package aggregdepscore
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ecosystemsByPurlType are the ecosystems of the package-url types that can be evaluated
+var ecosystemsByPurlType = map[string]string{
+	"npm":    "npm",
+	"pypi":   "pypi",
+	"maven":  "maven",
+	"cargo":  "crates.io",
+	"nuget":  "nuget",
+	"golang": "go",
+}
+
+// ParsePurl parses a package-url (a.k.a. "purl", see https://github.com/package-url/purl-spec)
+// such as "pkg:npm/%40babel/core@7.24.0" into a package.
+// The version is required; qualifiers and subpath are ignored.
+//
+// Names follow the conventions of the deps.dev client:
+// "group:artifact" for Maven, the full module path for Go and "@scope/name" for scoped npm packages.
+func ParsePurl(purl string) (Package, error) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return Package{}, fmt.Errorf("invalid purl %q: missing pkg: scheme", purl)
+	}
+
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	rest = strings.Trim(rest, "/")
+
+	purlType, rest, ok := strings.Cut(rest, "/")
+	if !ok {
+		return Package{}, fmt.Errorf("invalid purl %q: missing name", purl)
+	}
+	purlType = strings.ToLower(purlType)
+
+	ecosystem, ok := ecosystemsByPurlType[purlType]
+	if !ok {
+		return Package{}, fmt.Errorf("unsupported purl type %q", purlType)
+	}
+
+	i := strings.LastIndex(rest, "@")
+	if i < 0 {
+		return Package{}, fmt.Errorf("invalid purl %q: missing version", purl)
+	}
+	rawVersion := rest[i+1:]
+	rest = rest[:i]
+
+	version, err := url.PathUnescape(rawVersion)
+	if err != nil || version == "" {
+		return Package{}, fmt.Errorf("invalid purl %q: invalid version", purl)
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(rest, "/") {
+		if segment == "" {
+			continue
+		}
+
+		s, err := url.PathUnescape(segment)
+		if err != nil {
+			return Package{}, fmt.Errorf("invalid purl %q: %w", purl, err)
+		}
+		segments = append(segments, s)
+	}
+
+	if len(segments) == 0 {
+		return Package{}, fmt.Errorf("invalid purl %q: missing name", purl)
+	}
+
+	namespace := strings.Join(segments[:len(segments)-1], "/")
+	name := segments[len(segments)-1]
+
+	switch purlType {
+	case "maven":
+		if namespace == "" {
+			return Package{}, fmt.Errorf("invalid purl %q: missing Maven group", purl)
+		}
+		name = namespace + ":" + name
+	case "pypi", "cargo", "nuget":
+		if namespace != "" {
+			return Package{}, fmt.Errorf("invalid purl %q: unexpected namespace", purl)
+		}
+	default:
+		if namespace != "" {
+			name = namespace + "/" + name
+		}
+	}
+
+	return Package{Ecosystem: ecosystem, Name: name, Version: version}, nil
+}
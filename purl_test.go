@@ -0,0 +1,47 @@
+package aggregdepscore
+
+import "testing"
+
+func TestParsePurl(t *testing.T) {
+	testCases := []struct {
+		purl     string
+		expected Package
+		err      bool
+	}{
+		{purl: "pkg:npm/express@4.18.2", expected: Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"}},
+		{purl: "pkg:npm/%40babel/core@7.24.0", expected: Package{Ecosystem: "npm", Name: "@babel/core", Version: "7.24.0"}},
+		{purl: "pkg:pypi/requests@2.31.0?os=linux#src", expected: Package{Ecosystem: "pypi", Name: "requests", Version: "2.31.0"}},
+		{purl: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.17.0", expected: Package{Ecosystem: "maven", Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.17.0"}},
+		{purl: "pkg:cargo/serde@1.0.200", expected: Package{Ecosystem: "crates.io", Name: "serde", Version: "1.0.200"}},
+		{purl: "pkg:nuget/Newtonsoft.Json@13.0.3", expected: Package{Ecosystem: "nuget", Name: "Newtonsoft.Json", Version: "13.0.3"}},
+		{purl: "pkg:golang/github.com/google/uuid@v1.6.0", expected: Package{Ecosystem: "go", Name: "github.com/google/uuid", Version: "v1.6.0"}},
+		{purl: "pkg:NPM/left-pad@1.3.0", expected: Package{Ecosystem: "npm", Name: "left-pad", Version: "1.3.0"}},
+		{purl: "npm/express@4.18.2", err: true},
+		{purl: "pkg:npm/express", err: true},
+		{purl: "pkg:npm/express@", err: true},
+		{purl: "pkg:gem/rails@7.1.0", err: true},
+		{purl: "pkg:maven/jackson-databind@2.17.0", err: true},
+		{purl: "pkg:pypi/ns/requests@2.31.0", err: true},
+		{purl: "pkg:npm", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.purl, func(t *testing.T) {
+			actual, err := ParsePurl(tc.purl)
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package aggregdepscore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RepositoryCandidate is a source repository that may be the one of a package.
+type RepositoryCandidate struct {
+	Repository string
+	// Verified is true when the link between the package and the repository
+	// was established by an attestation or by the ecosystem itself
+	// (SLSA attestation or Go module origin for deps.dev)
+	// and not only by unverified package metadata.
+	Verified bool
+}
+
+// RepositoryCandidates is what a RepositorySelector chooses from.
+type RepositoryCandidates struct {
+	Package    Package
+	Candidates []RepositoryCandidate
+	// DeclaredURLs are the homepage and source repository URLs
+	// declared in the metadata of the package
+	DeclaredURLs []string
+}
+
+// DiscardedRepository is a candidate that was not selected, and why.
+type DiscardedRepository struct {
+	Repository string
+	Reason     string
+}
+
+// RepositorySelection is the result of a RepositorySelector.
+type RepositorySelection struct {
+	Repository string
+	Discarded  []DiscardedRepository
+}
+
+// RepositoryScorer returns the OSSF scorecard overall score (between 0 and 10) of a repository.
+type RepositoryScorer interface {
+	GetRepositoryScore(ctx context.Context, repository string) (float64, error)
+}
+
+// RepositorySelector chooses the source repository of a package
+// when several repositories are associated with it.
+//
+// Discarded candidates must be reported in the selection
+// so that a hijacked or mislinked repository cannot go unnoticed.
+type RepositorySelector interface {
+	SelectRepository(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer) (RepositorySelection, error)
+}
+
+// FirstRepositorySelector selects the first candidate.
+type FirstRepositorySelector struct{}
+
+// VerifiedRepositorySelector keeps the candidates that are verified
+// or that match a URL declared in the package metadata.
+// If more than one candidate remains (or none), the choice is delegated to Fallback.
+type VerifiedRepositorySelector struct {
+	Fallback RepositorySelector
+}
+
+// LowestScoreRepositorySelector selects the candidate with the lowest OSSF scorecard score;
+// this is the conservative choice.
+// Candidates whose score cannot be fetched (for instance without a scorecard) have the lowest score,
+// so that a hijacked repository cannot be avoided by having no scorecard.
+type LowestScoreRepositorySelector struct{}
+
+// HighestScoreRepositorySelector selects the candidate with the highest OSSF scorecard score.
+// Candidates whose score cannot be fetched have the lowest score.
+type HighestScoreRepositorySelector struct{}
+
+// compile-time interface checks
+var _ RepositorySelector = &FirstRepositorySelector{}
+var _ RepositorySelector = &VerifiedRepositorySelector{}
+var _ RepositorySelector = &LowestScoreRepositorySelector{}
+var _ RepositorySelector = &HighestScoreRepositorySelector{}
+
+func (s *FirstRepositorySelector) SelectRepository(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer) (RepositorySelection, error) {
+	if len(candidates.Candidates) == 0 {
+		return RepositorySelection{}, errors.New("no repository candidate")
+	}
+
+	selection := RepositorySelection{Repository: candidates.Candidates[0].Repository}
+
+	for _, c := range candidates.Candidates[1:] {
+		selection.Discarded = append(selection.Discarded, DiscardedRepository{
+			Repository: c.Repository,
+			Reason:     "not the first candidate",
+		})
+	}
+
+	return selection, nil
+}
+
+func (s *VerifiedRepositorySelector) SelectRepository(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer) (RepositorySelection, error) {
+	declared := make(map[string]struct{})
+	for _, url := range candidates.DeclaredURLs {
+		declared[normalizeRepository(repositoryFromURL(url))] = struct{}{}
+	}
+
+	var verified []RepositoryCandidate
+	var discarded []DiscardedRepository
+
+	for _, c := range candidates.Candidates {
+		if _, ok := declared[normalizeRepository(c.Repository)]; ok || c.Verified {
+			verified = append(verified, c)
+			continue
+		}
+
+		discarded = append(discarded, DiscardedRepository{
+			Repository: c.Repository,
+			Reason:     "neither verified nor declared in package metadata",
+		})
+	}
+
+	if len(verified) == 1 {
+		return RepositorySelection{Repository: verified[0].Repository, Discarded: discarded}, nil
+	}
+
+	if s.Fallback == nil {
+		return RepositorySelection{}, fmt.Errorf("found %d verified repositories among %d candidates and no fallback", len(verified), len(candidates.Candidates))
+	}
+
+	if len(verified) == 0 {
+		// nothing could be verified, let the fallback choose among all candidates
+		return s.Fallback.SelectRepository(ctx, candidates, scorer)
+	}
+
+	selection, err := s.Fallback.SelectRepository(ctx, RepositoryCandidates{
+		Package:      candidates.Package,
+		Candidates:   verified,
+		DeclaredURLs: candidates.DeclaredURLs,
+	}, scorer)
+	if err != nil {
+		return RepositorySelection{}, err
+	}
+
+	selection.Discarded = append(selection.Discarded, discarded...)
+
+	return selection, nil
+}
+
+func (s *LowestScoreRepositorySelector) SelectRepository(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer) (RepositorySelection, error) {
+	return selectByScore(ctx, candidates, scorer, func(score, best float64) bool { return score < best }, "a lower-scoring repository was selected")
+}
+
+func (s *HighestScoreRepositorySelector) SelectRepository(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer) (RepositorySelection, error) {
+	return selectByScore(ctx, candidates, scorer, func(score, best float64) bool { return score > best }, "a higher-scoring repository was selected")
+}
+
+func selectByScore(ctx context.Context, candidates RepositoryCandidates, scorer RepositoryScorer, better func(score, best float64) bool, reason string) (RepositorySelection, error) {
+	if len(candidates.Candidates) == 0 {
+		return RepositorySelection{}, errors.New("no repository candidate")
+	}
+
+	// no need to fetch scores if there is no choice to make
+	if len(candidates.Candidates) == 1 {
+		return RepositorySelection{Repository: candidates.Candidates[0].Repository}, nil
+	}
+
+	if scorer == nil {
+		return RepositorySelection{}, errors.New("selecting a repository by score requires a repository scorer")
+	}
+
+	// scoreErrs are the errors of the candidates that could not be scored, by index
+	scoreErrs := make(map[int]error)
+	bestIndex := -1
+	bestScore := math.NaN()
+
+	for i, c := range candidates.Candidates {
+		score, err := scorer.GetRepositoryScore(ctx, c.Repository)
+		if err != nil {
+			if ctx.Err() != nil {
+				return RepositorySelection{}, err
+			}
+
+			// an unscored candidate is the worst one
+			scoreErrs[i] = err
+			score = math.Inf(-1)
+		}
+
+		if bestIndex == -1 || better(score, bestScore) {
+			bestIndex = i
+			bestScore = score
+		}
+	}
+
+	selection := RepositorySelection{Repository: candidates.Candidates[bestIndex].Repository}
+
+	for i, c := range candidates.Candidates {
+		if i == bestIndex {
+			continue
+		}
+
+		discarded := DiscardedRepository{Repository: c.Repository, Reason: reason}
+		if err, ok := scoreErrs[i]; ok {
+			discarded.Reason = fmt.Sprintf("could not get score: %v", err)
+		}
+
+		selection.Discarded = append(selection.Discarded, discarded)
+	}
+
+	return selection, nil
+}
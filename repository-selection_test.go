@@ -0,0 +1,116 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type testRepositoryScorer struct {
+	scoreByRepository map[string]float64
+}
+
+func (s *testRepositoryScorer) GetRepositoryScore(ctx context.Context, repository string) (float64, error) {
+	score, ok := s.scoreByRepository[repository]
+	if !ok {
+		return 0, fmt.Errorf("no scorecard for %q", repository)
+	}
+
+	return score, nil
+}
+
+func TestRepositorySelectors(t *testing.T) {
+	scorer := &testRepositoryScorer{scoreByRepository: map[string]float64{
+		"github.com/legit/package":     7.5,
+		"github.com/attacker/package":  9.9,
+		"github.com/abandoned/package": 2.1,
+	}}
+
+	candidates := RepositoryCandidates{
+		Package: Package{Ecosystem: "npm", Name: "package", Version: "1.0.0"},
+		Candidates: []RepositoryCandidate{
+			{Repository: "github.com/legit/package"},
+			{Repository: "github.com/attacker/package"},
+			{Repository: "github.com/abandoned/package"},
+			{Repository: "github.com/unscored/package"},
+		},
+		DeclaredURLs: []string{"git+https://github.com/Legit/package.git"},
+	}
+
+	for _, each := range []struct {
+		name        string
+		selector    RepositorySelector
+		expected    string
+		nbDiscarded int
+	}{
+		{"first", &FirstRepositorySelector{}, "github.com/legit/package", 3},
+		{"lowest", &LowestScoreRepositorySelector{}, "github.com/unscored/package", 3},
+		{"highest", &HighestScoreRepositorySelector{}, "github.com/attacker/package", 3},
+		{"verified", &VerifiedRepositorySelector{}, "github.com/legit/package", 3},
+	} {
+		t.Run(each.name, func(t *testing.T) {
+			selection, err := each.selector.SelectRepository(context.Background(), candidates, scorer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if selection.Repository != each.expected {
+				t.Errorf("expected %q, got %q", each.expected, selection.Repository)
+			}
+
+			if len(selection.Discarded) != each.nbDiscarded {
+				t.Errorf("expected %d discarded repositories, got %+v", each.nbDiscarded, selection.Discarded)
+			}
+
+			for _, d := range selection.Discarded {
+				if d.Repository == selection.Repository {
+					t.Errorf("selected repository %q also reported as discarded", d.Repository)
+				}
+				if d.Reason == "" {
+					t.Errorf("no reason given for discarding %q", d.Repository)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifiedRepositorySelectorFallback(t *testing.T) {
+	scorer := &testRepositoryScorer{scoreByRepository: map[string]float64{
+		"github.com/a/package": 7.5,
+		"github.com/b/package": 3.0,
+		"github.com/c/package": 1.0,
+	}}
+
+	selector := &VerifiedRepositorySelector{Fallback: &LowestScoreRepositorySelector{}}
+
+	// two candidates are verified, the fallback chooses among them only
+	selection, err := selector.SelectRepository(context.Background(), RepositoryCandidates{
+		Candidates: []RepositoryCandidate{
+			{Repository: "github.com/a/package", Verified: true},
+			{Repository: "github.com/b/package", Verified: true},
+			{Repository: "github.com/c/package"},
+		},
+	}, scorer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if selection.Repository != "github.com/b/package" {
+		t.Errorf("expected %q, got %q", "github.com/b/package", selection.Repository)
+	}
+
+	if len(selection.Discarded) != 2 {
+		t.Errorf("expected 2 discarded repositories, got %+v", selection.Discarded)
+	}
+
+	// nothing is verified and there is no fallback
+	_, err = (&VerifiedRepositorySelector{}).SelectRepository(context.Background(), RepositoryCandidates{
+		Candidates: []RepositoryCandidate{
+			{Repository: "github.com/a/package"},
+			{Repository: "github.com/b/package"},
+		},
+	}, scorer)
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
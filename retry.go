@@ -0,0 +1,181 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	api "deps.dev/api/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy decides how deps.dev RPCs failing with a transient error are retried:
+// UNAVAILABLE, RESOURCE_EXHAUSTED, ABORTED, and DEADLINE_EXCEEDED unless the deadline is the caller's.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of an RPC, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled at each retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts (unlimited if 0)
+	MaxBackoff time.Duration
+}
+
+func (p *RetryPolicy) validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", p.MaxAttempts)
+	}
+
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("backoffs must be positive, got %s and %s", p.InitialBackoff, p.MaxBackoff)
+	}
+
+	return nil
+}
+
+// backoff is the delay before the given retry (1 for the first retry)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return d
+}
+
+// WithDepsDotDevRetry retries the deps.dev RPCs failing with a transient error;
+// by default RPCs are not retried.
+func WithDepsDotDevRetry(policy RetryPolicy) DepsDotDevOption {
+	return func(c *client) {
+		c.retry = &policy
+	}
+}
+
+// WithDepsDotDevRateLimit spaces deps.dev RPCs (including retries)
+// so that no more than requestsPerSecond are sent;
+// by default RPCs are not rate-limited.
+func WithDepsDotDevRateLimit(requestsPerSecond float64) DepsDotDevOption {
+	return func(c *client) {
+		c.rateLimit = requestsPerSecond
+	}
+}
+
+// retryingInsightsClient retries and rate-limits deps.dev RPCs
+type retryingInsightsClient struct {
+	api.InsightsClient
+	// retry is nil if RPCs are not retried
+	retry *RetryPolicy
+	// limiter is nil if RPCs are not rate-limited
+	limiter *rateLimiter
+}
+
+// call calls f until it succeeds, fails with a permanent error or runs out of attempts
+func call[T any](ctx context.Context, c *retryingInsightsClient, f func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		var zero T
+
+		if c.limiter != nil {
+			err := c.limiter.wait(ctx)
+			if err != nil {
+				return zero, err
+			}
+		}
+
+		result, err := f()
+		if err == nil || c.retry == nil || attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, err
+		}
+	}
+}
+
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *retryingInsightsClient) GetVersion(ctx context.Context, in *api.GetVersionRequest, opts ...grpc.CallOption) (*api.Version, error) {
+	return call(ctx, c, func() (*api.Version, error) {
+		return c.InsightsClient.GetVersion(ctx, in, opts...)
+	})
+}
+
+func (c *retryingInsightsClient) GetRequirements(ctx context.Context, in *api.GetRequirementsRequest, opts ...grpc.CallOption) (*api.Requirements, error) {
+	return call(ctx, c, func() (*api.Requirements, error) {
+		return c.InsightsClient.GetRequirements(ctx, in, opts...)
+	})
+}
+
+func (c *retryingInsightsClient) GetDependencies(ctx context.Context, in *api.GetDependenciesRequest, opts ...grpc.CallOption) (*api.Dependencies, error) {
+	return call(ctx, c, func() (*api.Dependencies, error) {
+		return c.InsightsClient.GetDependencies(ctx, in, opts...)
+	})
+}
+
+func (c *retryingInsightsClient) GetProject(ctx context.Context, in *api.GetProjectRequest, opts ...grpc.CallOption) (*api.Project, error) {
+	return call(ctx, c, func() (*api.Project, error) {
+		return c.InsightsClient.GetProject(ctx, in, opts...)
+	})
+}
+
+// rateLimiter hands out evenly spaced time slots
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the next free slot, or until ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,137 @@
+package aggregdepscore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	api "deps.dev/api/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+// flakyInsightsClient fails the first calls to GetVersion with a given code
+type flakyInsightsClient struct {
+	api.InsightsClient
+
+	mutex    sync.Mutex
+	code     codes.Code
+	failures int
+	calls    int
+}
+
+func (c *flakyInsightsClient) GetVersion(ctx context.Context, in *api.GetVersionRequest, opts ...grpc.CallOption) (*api.Version, error) {
+	c.mutex.Lock()
+	c.calls++
+	fail := c.calls <= c.failures
+	c.mutex.Unlock()
+
+	if fail {
+		return nil, status.Error(c.code, "flaky")
+	}
+
+	return c.InsightsClient.GetVersion(ctx, in, opts...)
+}
+
+func TestDepsDotDevRetry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		code          codes.Code
+		failures      int
+		retry         *RetryPolicy
+		expectedCalls int
+		expectedCode  codes.Code
+	}{
+		{name: "without retries", code: codes.Unavailable, failures: 1, expectedCalls: 1, expectedCode: codes.Unavailable},
+		{name: "transient error", code: codes.Unavailable, failures: 2, retry: &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, expectedCalls: 3, expectedCode: codes.OK},
+		{name: "out of attempts", code: codes.ResourceExhausted, failures: 5, retry: &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, expectedCalls: 3, expectedCode: codes.ResourceExhausted},
+		{name: "permanent error", code: codes.NotFound, failures: 1, retry: &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, expectedCalls: 1, expectedCode: codes.NotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := depsdotdevfake.New()
+			fake.AddProject("github.com/expressjs/express", 8)
+			fake.AddVersion(api.System_NPM, "express", "4.18.2", []string{"github.com/expressjs/express"})
+
+			flaky := &flakyInsightsClient{InsightsClient: fake, code: tc.code, failures: tc.failures}
+
+			opts := []DepsDotDevOption{WithInsightsClient(flaky)}
+			if tc.retry != nil {
+				opts = append(opts, WithDepsDotDevRetry(*tc.retry))
+			}
+
+			c, err := NewDepsDotDevClient(opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = c.EvaluateIntrinsicTrustworthiness(context.Background(), Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"})
+			if status.Code(err) != tc.expectedCode {
+				t.Errorf("expected code %s, got %v", tc.expectedCode, err)
+			}
+
+			if flaky.calls != tc.expectedCalls {
+				t.Errorf("expected %d calls to GetVersion, got %d", tc.expectedCalls, flaky.calls)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	var actual []time.Duration
+	for retry := 1; retry <= 5; retry++ {
+		actual = append(actual, p.backoff(retry))
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected backoffs %v, got %v", expected, actual)
+			break
+		}
+	}
+}
+
+func TestDepsDotDevRateLimit(t *testing.T) {
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/expressjs/express", 8)
+	fake.AddVersion(api.System_NPM, "express", "4.18.2", []string{"github.com/expressjs/express"})
+
+	c, err := NewDepsDotDevClient(WithInsightsClient(fake), WithDepsDotDevRateLimit(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.GetDirectDependencies(context.Background(), Package{Ecosystem: "npm", Name: "express", Version: "4.18.2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the first call is immediate, the next ones wait 10ms each
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected 3 calls at 100 per second to take at least 20ms, took %s", elapsed)
+	}
+}
+
+func TestInvalidDepsDotDevResilienceOptions(t *testing.T) {
+	for _, opt := range []DepsDotDevOption{
+		WithDepsDotDevRetry(RetryPolicy{}),
+		WithDepsDotDevRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: -time.Second}),
+		WithDepsDotDevRateLimit(-1),
+	} {
+		_, err := NewDepsDotDevClient(WithInsightsClient(depsdotdevfake.New()), opt)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	}
+}
@@ -13,27 +13,52 @@ type ScoreTrustworthinessConverter interface {
 	TrustworthinessFromScore(score float64) float64
 }
 
-type DefaultScoreTrustworthinessConverter struct{}
+// DefaultScoreTrustworthinessConverter converts scores to trustworthiness and back
+// as described in the design paper.
+// Zero fields use the default values (see ModelParameters).
+type DefaultScoreTrustworthinessConverter struct {
+	// MinTrustworthiness is the trustworthiness of a score of 0
+	MinTrustworthiness float64
+	// TrustworthinessOffset is noted as "k" in the design paper
+	TrustworthinessOffset float64
+}
 
 // compile-time interface checks
 var _ ScoreTrustworthinessConverter = &DefaultScoreTrustworthinessConverter{}
 
 func (c *DefaultScoreTrustworthinessConverter) ScoreFromTrustworthiness(trustworthiness float64) float64 {
 	t := trustworthiness
-	k := trustworthinessOffset
+	k := c.offset()
+	min := c.min()
 
-	if t < minTrustworthiness {
+	if t < min {
 		return 0
 	}
 
-	return ((1 - math.Pow(k, (1-(1-t)/0.2))) /
+	return ((1 - math.Pow(k, (1-(1-t)/(1-min)))) /
 		(1 - k))
 }
 
 func (c *DefaultScoreTrustworthinessConverter) TrustworthinessFromScore(score float64) float64 {
-	k := trustworthinessOffset
-	min := minTrustworthiness
+	k := c.offset()
+	min := c.min()
 
 	return 1 - (1-min)*(1-math.Log(1+(k-1)*score)/
 		math.Log(k))
 }
+
+func (c *DefaultScoreTrustworthinessConverter) min() float64 {
+	if c.MinTrustworthiness == 0 {
+		return minTrustworthiness
+	}
+
+	return c.MinTrustworthiness
+}
+
+func (c *DefaultScoreTrustworthinessConverter) offset() float64 {
+	if c.TrustworthinessOffset == 0 {
+		return trustworthinessOffset
+	}
+
+	return c.TrustworthinessOffset
+}
@@ -18,3 +18,21 @@ func TestDefaultScoreTrustworthinessConverter(t *testing.T) {
 		}
 	}
 }
+
+func TestParametrizedScoreTrustworthinessConverter(t *testing.T) {
+	c := DefaultScoreTrustworthinessConverter{MinTrustworthiness: 0.5, TrustworthinessOffset: 10}
+
+	if score := c.ScoreFromTrustworthiness(0.6); score <= 0 {
+		t.Errorf("expected a positive score above the min trustworthiness, got %g", score)
+	}
+
+	if score := c.ScoreFromTrustworthiness(0.49); score != 0 {
+		t.Errorf("expected a zero score below the min trustworthiness, got %g", score)
+	}
+
+	for _, score := range []float64{0, 0.25, 0.5, 1} {
+		if actual := c.ScoreFromTrustworthiness(c.TrustworthinessFromScore(score)); math.Abs(actual-score) > 1e-13 {
+			t.Errorf("failed inverse test for %g: got %g", score, actual)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package aggregdepscore
+
+import (
+	"context"
+
+	api "deps.dev/api/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentationName is the name of the OpenTelemetry tracer
+const instrumentationName = "github.com/DataDog/aggregated-dependency-score"
+
+func defaultTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(instrumentationName)
+}
+
+func packageAttributes(p Package) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("package.ecosystem", p.Ecosystem),
+		attribute.String("package.name", p.Name),
+		attribute.String("package.version", p.Version),
+	}
+}
+
+func versionKeyAttributes(k *api.VersionKey) []attribute.KeyValue {
+	if k == nil {
+		return nil
+	}
+
+	ecosystem, err := depsdotdevEcosystemString(k.System)
+	if err != nil {
+		ecosystem = k.System.String()
+	}
+
+	return packageAttributes(Package{Ecosystem: ecosystem, Name: k.Name, Version: k.Version})
+}
+
+// endSpan records err (if any) on the span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// tracingInsightsClient creates a span for each deps.dev RPC
+type tracingInsightsClient struct {
+	api.InsightsClient
+	tracer trace.Tracer
+}
+
+func (c *tracingInsightsClient) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "deps.dev/"+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", "deps_dev.v3.Insights"),
+			attribute.String("rpc.method", method),
+		),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+func (c *tracingInsightsClient) end(span trace.Span, err error) {
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(status.Code(err))))
+	endSpan(span, err)
+}
+
+func (c *tracingInsightsClient) GetVersion(ctx context.Context, in *api.GetVersionRequest, opts ...grpc.CallOption) (*api.Version, error) {
+	ctx, span := c.start(ctx, "GetVersion", versionKeyAttributes(in.VersionKey)...)
+	result, err := c.InsightsClient.GetVersion(ctx, in, opts...)
+	c.end(span, err)
+	return result, err
+}
+
+func (c *tracingInsightsClient) GetRequirements(ctx context.Context, in *api.GetRequirementsRequest, opts ...grpc.CallOption) (*api.Requirements, error) {
+	ctx, span := c.start(ctx, "GetRequirements", versionKeyAttributes(in.VersionKey)...)
+	result, err := c.InsightsClient.GetRequirements(ctx, in, opts...)
+	c.end(span, err)
+	return result, err
+}
+
+func (c *tracingInsightsClient) GetDependencies(ctx context.Context, in *api.GetDependenciesRequest, opts ...grpc.CallOption) (*api.Dependencies, error) {
+	ctx, span := c.start(ctx, "GetDependencies", versionKeyAttributes(in.VersionKey)...)
+	result, err := c.InsightsClient.GetDependencies(ctx, in, opts...)
+	if err == nil {
+		span.SetAttributes(attribute.Int("depsdotdev.nb_nodes", len(result.Nodes)))
+	}
+	c.end(span, err)
+	return result, err
+}
+
+func (c *tracingInsightsClient) GetProject(ctx context.Context, in *api.GetProjectRequest, opts ...grpc.CallOption) (*api.Project, error) {
+	var attributes []attribute.KeyValue
+	if in.ProjectKey != nil {
+		attributes = append(attributes, attribute.String("project.id", in.ProjectKey.Id))
+	}
+
+	ctx, span := c.start(ctx, "GetProject", attributes...)
+	result, err := c.InsightsClient.GetProject(ctx, in, opts...)
+	c.end(span, err)
+	return result, err
+}
@@ -0,0 +1,92 @@
+package aggregdepscore
+
+import (
+	"context"
+	"testing"
+
+	api "deps.dev/api/v3"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/DataDog/aggregated-dependency-score/internal/depsdotdevfake"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	fake := depsdotdevfake.New()
+	fake.AddProject("github.com/acme/app", 8)
+	fake.AddProject("github.com/acme/lib", 6)
+	fake.AddVersion(api.System_NPM, "app", "1.0.0", []string{"github.com/acme/app"},
+		&api.VersionKey{System: api.System_NPM, Name: "lib", Version: "2.0.0"})
+	fake.AddVersion(api.System_NPM, "lib", "2.0.0", []string{"github.com/acme/lib"})
+
+	depsdotdev, err := NewDepsDotDevClient(WithInsightsClient(fake), WithDepsDotDevTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluator, err := NewEvaluator(depsdotdev, depsdotdev, WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = evaluator.EvaluateScore(context.Background(), Package{Ecosystem: "npm", Name: "app", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+
+	spanByID := make(map[string]tracetest.SpanStub)
+	countByName := make(map[string]int)
+	for _, span := range spans {
+		spanByID[span.SpanContext.SpanID().String()] = span
+		countByName[span.Name]++
+	}
+
+	for name, expected := range map[string]int{
+		"aggregdepscore.Evaluate":  1,
+		"aggregdepscore.evaluate":  2,
+		"deps.dev/GetVersion":      2,
+		"deps.dev/GetProject":      2,
+		"deps.dev/GetDependencies": 2,
+	} {
+		if countByName[name] != expected {
+			t.Errorf("expected %d spans named %q, got %d", expected, name, countByName[name])
+		}
+	}
+
+	for _, span := range spans {
+		if span.Name == "aggregdepscore.Evaluate" {
+			if span.Parent.IsValid() {
+				t.Errorf("expected Evaluate span to be a root span")
+			}
+			continue
+		}
+
+		// every other span must be a descendant of an evaluate span of the right package
+		parent, ok := spanByID[span.Parent.SpanID().String()]
+		if !ok {
+			t.Errorf("span %q has no parent", span.Name)
+			continue
+		}
+
+		if span.Name == "deps.dev/GetVersion" && attributeValue(span.Attributes, "package.name") != attributeValue(parent.Attributes, "package.name") {
+			t.Errorf("RPC span for package %q has a parent span for package %q",
+				attributeValue(span.Attributes, "package.name"), attributeValue(parent.Attributes, "package.name"))
+		}
+	}
+}
+
+func attributeValue(attributes []attribute.KeyValue, key string) string {
+	for _, a := range attributes {
+		if string(a.Key) == key {
+			return a.Value.Emit()
+		}
+	}
+
+	return ""
+}
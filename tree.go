@@ -0,0 +1,123 @@
+package aggregdepscore
+
+import (
+	"math"
+	"sort"
+)
+
+// WithEvaluationTree makes Evaluate record the tree of evaluated packages
+// in Evaluation.Tree.
+//
+// Because packages are evaluated once per path from the root,
+// the tree can be much larger than the dependency graph.
+func WithEvaluationTree() EvaluatorOption {
+	return func(config *evaluatorConfig) {
+		config.tree = true
+	}
+}
+
+// Node is a package in the tree of evaluated packages (see WithEvaluationTree).
+// A package reachable through several paths appears once per path.
+type Node struct {
+	Package Package
+	Depth   int
+
+	// IntrinsicTrustworthiness is zero for truncated nodes
+	// with the TruncateAssumeTrustworthiness policy and for cycle cutoffs
+	IntrinsicTrustworthiness float64
+	// AggregatedTrustworthiness is zero for cycle cutoffs
+	AggregatedTrustworthiness    float64
+	LogAggregatedTrustworthiness float64
+	// Penalty is the factor this node multiplies the aggregated trustworthiness of its parent by,
+	// that is T'^e (see the design paper), or 1 for cycle cutoffs.
+	// With WithTrustDomainGrouping, the actual penalty is computed per trust domain.
+	Penalty float64
+
+	// Fallback is set if the intrinsic trustworthiness of the package was assumed
+	Fallback *Fallback
+	// Truncation is set if the dependencies of the package were not evaluated
+	// because of limits
+	Truncation *Truncation
+	// Cycle is set if the package was not evaluated because it closes a dependency cycle
+	Cycle *Cycle
+
+	Children []*Node
+
+	// exponent is the transitive trustworthiness exponent of the evaluation, 0 for the default
+	exponent float64
+}
+
+// Walk calls f on node and its descendants, depth first, parents before children;
+// the descendants of a node are skipped if f returns false.
+func (n *Node) Walk(f func(*Node) bool) {
+	if !f(n) {
+		return
+	}
+
+	for _, child := range n.Children {
+		child.Walk(f)
+	}
+}
+
+// SortWorstFirst sorts the children of node and of its descendants
+// from the highest penalty (lowest Penalty factor) to the lowest.
+func (n *Node) SortWorstFirst() {
+	n.Walk(func(node *Node) bool {
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return node.Children[i].LogAggregatedTrustworthiness < node.Children[j].LogAggregatedTrustworthiness
+		})
+		return true
+	})
+}
+
+// LogContributions attributes the log of the aggregated trustworthiness of node
+// to the packages of its tree.
+// For each path it is on, a package contributes e^depth (depth relative to node)
+// times the log of its own trustworthiness, that is its aggregated trustworthiness
+// without the penalties of its dependencies (mostly its intrinsic trustworthiness).
+// The contributions sum to node.LogAggregatedTrustworthiness;
+// the contribution of a package with a zero trustworthiness is -Inf.
+func (n *Node) LogContributions() map[Package]float64 {
+	contributions := make(map[Package]float64)
+
+	var walk func(node *Node, weight float64)
+	walk = func(node *Node, weight float64) {
+		if node.Cycle == nil {
+			contributions[node.Package] += weight * ownLogTrustworthiness(node)
+		}
+
+		for _, child := range node.Children {
+			walk(child, weight*node.transitiveExponent())
+		}
+	}
+	walk(n, 1)
+
+	return contributions
+}
+
+func newCycleCutoffNode(cycle Cycle, depth int) *Node {
+	dep := cycle.Packages[len(cycle.Packages)-1]
+
+	return &Node{
+		Package: dep,
+		Depth:   depth,
+		Penalty: 1,
+		Cycle:   &cycle,
+	}
+}
+
+func (n *Node) setAggregated(logAggregated float64) {
+	n.LogAggregatedTrustworthiness = logAggregated
+	n.AggregatedTrustworthiness = math.Exp(logAggregated)
+	n.Penalty = math.Exp(n.transitiveExponent() * logAggregated)
+}
+
+// transitiveExponent is the exponent of the aggregated trustworthiness of the children of n
+// in the aggregated trustworthiness of n (see WithParameters)
+func (n *Node) transitiveExponent() float64 {
+	if n.exponent == 0 {
+		return transitiveTrustworthinessExponent
+	}
+
+	return n.exponent
+}
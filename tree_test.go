@@ -0,0 +1,144 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluationTree(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{
+				"A": 0.92,
+				"B": 0.94,
+				"C": 0.7,
+				// D is unknown and will fall back
+			},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{
+				"A": {"B", "C"},
+				"B": {"A", "D"},
+				"C": {},
+				"D": {},
+			},
+		},
+		WithIntrinsicFallback(0.8),
+		WithEvaluationTree(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tree := evaluation.Tree
+	if tree == nil {
+		t.Fatalf("expected a tree")
+	}
+
+	if tree.AggregatedTrustworthiness != evaluation.Trustworthiness {
+		t.Errorf("expected root to have the aggregated trustworthiness of the evaluation, got %f", tree.AggregatedTrustworthiness)
+	}
+
+	tree.SortWorstFirst()
+
+	var lines []string
+	tree.Walk(func(n *Node) bool {
+		line := fmt.Sprintf("%s%s", strings.Repeat(" ", n.Depth), n.Package.Name)
+		if n.Cycle != nil {
+			line += " cycle"
+		}
+		if n.Fallback != nil {
+			line += " fallback"
+		}
+		lines = append(lines, line)
+		return true
+	})
+
+	// B (0.94 × 0.8^1.5 ≈ 0.673) is worse than C (0.7),
+	// and the cycle cutoff has no penalty so it comes last
+	expected := []string{"A", " B", "  D fallback", "  A cycle", " C"}
+
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected tree:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	product := tree.IntrinsicTrustworthiness
+	for _, child := range tree.Children {
+		product *= child.Penalty
+	}
+	if math.Abs(product-tree.AggregatedTrustworthiness) > 1e-12 {
+		t.Errorf("expected penalties to multiply to the aggregated trustworthiness: %f != %f", product, tree.AggregatedTrustworthiness)
+	}
+}
+
+func TestNoEvaluationTreeByDefault(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{trustworthinessByName: map[string]float64{"A": 0.9}},
+		&testDependencyResolver{directDependencyNamesByName: map[string][]string{"A": {}}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluation.Tree != nil {
+		t.Errorf("expected no tree unless requested")
+	}
+}
+
+func TestLogContributions(t *testing.T) {
+	evaluator, err := NewEvaluator(
+		&testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{"A": 0.9, "B": 0.95, "C": 0.8},
+		},
+		&testDependencyResolver{
+			directDependencyNamesByName: map[string][]string{"A": {"B", "C"}, "B": {"C"}, "C": {}},
+		},
+		WithEvaluationTree(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluation, err := evaluator.Evaluate(context.Background(), Package{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contributions := evaluation.Tree.LogContributions()
+
+	// C is on two paths, at depths 1 and 2
+	expected := map[string]float64{
+		"A": math.Log(0.9),
+		"B": 1.5 * math.Log(0.95),
+		"C": (1.5 + 1.5*1.5) * math.Log(0.8),
+	}
+
+	total := 0.0
+	for p, contribution := range contributions {
+		if math.Abs(contribution-expected[p.Name]) > 1e-12 {
+			t.Errorf("%s: expected a contribution of %f, got %f", p.Name, expected[p.Name], contribution)
+		}
+		total += contribution
+	}
+
+	if len(contributions) != len(expected) {
+		t.Errorf("expected %d contributions, got %d", len(expected), len(contributions))
+	}
+
+	if math.Abs(total-evaluation.LogTrustworthiness) > 1e-12 {
+		t.Errorf("expected the contributions to sum to %f, got %f", evaluation.LogTrustworthiness, total)
+	}
+}
@@ -0,0 +1,209 @@
+package aggregdepscore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// TrustDomainPolicy decides how a group of sibling dependencies
+// published from the same source repository (a "trust domain")
+// is accounted for in the aggregated trustworthiness of their parent.
+//
+// Members of a trust domain share their publisher but not their dependencies,
+// so only their intrinsic trustworthiness is grouped:
+// Combine receives the intrinsic trustworthiness of each member of the group
+// and returns the one of the group as a whole.
+// The aggregated trustworthiness of the group is then this value
+// times the transitive part of the aggregated trustworthiness of each member,
+// and is used like the one of a single dependency.
+type TrustDomainPolicy interface {
+	Combine(intrinsicTrustworthiness []float64) float64
+}
+
+// LogTrustDomainPolicy is implemented by policies
+// that can combine the logarithms of trustworthiness values directly,
+// which avoids underflows with huge dependency graphs.
+// CombineLog must be equivalent to the logarithm of Combine.
+type LogTrustDomainPolicy interface {
+	TrustDomainPolicy
+	CombineLog(logIntrinsicTrustworthiness []float64) float64
+}
+
+// CountEachPolicy counts each member of a trust domain as an independent risk;
+// this is equivalent to not grouping dependencies at all.
+type CountEachPolicy struct{}
+
+// WorstMemberPolicy counts the publisher of a trust domain once,
+// using the lowest intrinsic trustworthiness of its members.
+// This reflects that the members of a monorepo share their publisher,
+// so depending on many of them is not much riskier than depending on one of them.
+type WorstMemberPolicy struct{}
+
+// GeometricMeanPolicy counts the publisher of a trust domain once,
+// using the geometric mean of the intrinsic trustworthiness of its members.
+type GeometricMeanPolicy struct{}
+
+// compile-time interface checks
+var _ LogTrustDomainPolicy = &CountEachPolicy{}
+var _ LogTrustDomainPolicy = &WorstMemberPolicy{}
+var _ LogTrustDomainPolicy = &GeometricMeanPolicy{}
+
+func (p *CountEachPolicy) Combine(intrinsicTrustworthiness []float64) float64 {
+	result := 1.0
+	for _, t := range intrinsicTrustworthiness {
+		result *= t
+	}
+
+	return result
+}
+
+func (p *CountEachPolicy) CombineLog(logIntrinsicTrustworthiness []float64) float64 {
+	result := 0.0
+	for _, l := range logIntrinsicTrustworthiness {
+		result += l
+	}
+
+	return result
+}
+
+func (p *WorstMemberPolicy) Combine(intrinsicTrustworthiness []float64) float64 {
+	result := 1.0
+	for _, t := range intrinsicTrustworthiness {
+		result = math.Min(result, t)
+	}
+
+	return result
+}
+
+func (p *WorstMemberPolicy) CombineLog(logIntrinsicTrustworthiness []float64) float64 {
+	result := 0.0
+	for _, l := range logIntrinsicTrustworthiness {
+		result = math.Min(result, l)
+	}
+
+	return result
+}
+
+func (p *GeometricMeanPolicy) Combine(intrinsicTrustworthiness []float64) float64 {
+	if len(intrinsicTrustworthiness) == 0 {
+		return 1.0
+	}
+
+	sumOfLogs := 0.0
+	for _, t := range intrinsicTrustworthiness {
+		sumOfLogs += math.Log(t)
+	}
+
+	return math.Exp(sumOfLogs / float64(len(intrinsicTrustworthiness)))
+}
+
+func (p *GeometricMeanPolicy) CombineLog(logIntrinsicTrustworthiness []float64) float64 {
+	if len(logIntrinsicTrustworthiness) == 0 {
+		return 0.0
+	}
+
+	sum := 0.0
+	for _, l := range logIntrinsicTrustworthiness {
+		sum += l
+	}
+
+	return sum / float64(len(logIntrinsicTrustworthiness))
+}
+
+// trustDomainGrouping groups dependencies by source repository
+type trustDomainGrouping struct {
+	policy       TrustDomainPolicy
+	repositories RepositoryResolver
+
+	cacheMutex sync.Mutex
+	cache      map[Package]string
+}
+
+type evaluatedDependency struct {
+	pkg Package
+	// logIntrinsic is the logarithm of the intrinsic trustworthiness
+	logIntrinsic float64
+	// logAggregated is the logarithm of the aggregated trustworthiness
+	logAggregated float64
+}
+
+// combine returns the logarithm of the aggregated trustworthiness of each trust domain
+// among the given sibling dependencies;
+// a dependency whose repository cannot be found is a trust domain of its own,
+// unless the lookup failed because ctx is done
+func (g *trustDomainGrouping) combine(ctx context.Context, deps []evaluatedDependency) ([]float64, error) {
+	var domains [][]evaluatedDependency
+	indexByRepository := make(map[string]int)
+
+	for _, dep := range deps {
+		repository, err := g.getRepository(ctx, dep.pkg)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("getting repository of %s: %w", dep.pkg, err)
+			}
+
+			domains = append(domains, []evaluatedDependency{dep})
+			continue
+		}
+
+		domain := normalizeRepository(repository)
+
+		i, ok := indexByRepository[domain]
+		if !ok {
+			i = len(domains)
+			indexByRepository[domain] = i
+			domains = append(domains, nil)
+		}
+		domains[i] = append(domains[i], dep)
+	}
+
+	result := make([]float64, len(domains))
+	for i, members := range domains {
+		intrinsic := make([]float64, len(members))
+		logTransitive := 0.0
+		for j, member := range members {
+			intrinsic[j] = member.logIntrinsic
+			logTransitive += member.logAggregated - member.logIntrinsic
+		}
+
+		result[i] = g.combineLog(intrinsic) + logTransitive
+	}
+
+	return result, nil
+}
+
+func (g *trustDomainGrouping) combineLog(members []float64) float64 {
+	if p, ok := g.policy.(LogTrustDomainPolicy); ok {
+		return p.CombineLog(members)
+	}
+
+	linear := make([]float64, len(members))
+	for i, l := range members {
+		linear[i] = math.Exp(l)
+	}
+
+	return math.Log(g.policy.Combine(linear))
+}
+
+func (g *trustDomainGrouping) getRepository(ctx context.Context, p Package) (string, error) {
+	g.cacheMutex.Lock()
+	repository, ok := g.cache[p]
+	g.cacheMutex.Unlock()
+
+	if ok {
+		return repository, nil
+	}
+
+	repository, err := g.repositories.GetRepository(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	g.cacheMutex.Lock()
+	g.cache[p] = repository
+	g.cacheMutex.Unlock()
+
+	return repository, nil
+}
@@ -0,0 +1,149 @@
+package aggregdepscore
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestTrustDomainGrouping(t *testing.T) {
+	intrinsic := &testRepositoryAwareEvaluator{
+		testIntrinsicTrustworthinessEvaluator: testIntrinsicTrustworthinessEvaluator{
+			trustworthinessByName: map[string]float64{
+				"app":             0.95,
+				"@aws-sdk/client": 0.9,
+				"@aws-sdk/core":   0.92,
+				"@aws-sdk/types":  0.94,
+				"left-pad":        0.85,
+				"tslib":           0.8,
+				"fast-xml":        0.7,
+				// the repository of mystery is unknown
+				"mystery": 0.6,
+			},
+		},
+		testRepositoryResolver: testRepositoryResolver{
+			repositoryByName: map[string]string{
+				"app":             "github.com/acme/app",
+				"@aws-sdk/client": "github.com/aws/aws-sdk-js-v3",
+				"@aws-sdk/core":   "github.com/aws/aws-sdk-js-v3",
+				"@aws-sdk/types":  "github.com/aws/aws-sdk-js-v3",
+				"left-pad":        "github.com/left-pad/left-pad",
+				"tslib":           "github.com/microsoft/tslib",
+				"fast-xml":        "github.com/naturalintelligence/fast-xml-parser",
+			},
+		},
+	}
+
+	deps := &testDependencyResolver{
+		directDependencyNamesByName: map[string][]string{
+			"app":             {"@aws-sdk/client", "@aws-sdk/core", "@aws-sdk/types", "left-pad", "mystery"},
+			"@aws-sdk/client": {"tslib"},
+			"@aws-sdk/core":   {"fast-xml"},
+			"@aws-sdk/types":  {},
+			"left-pad":        {},
+			"tslib":           {},
+			"fast-xml":        {},
+			"mystery":         {},
+		},
+	}
+
+	e := transitiveTrustworthinessExponent
+	// only the intrinsic trustworthiness of the members of a trust domain is grouped,
+	// the dependencies of each member are all accounted for
+	awsDependencies := math.Pow(0.8, e) * math.Pow(0.7, e)
+	// packages of other trust domains, including mystery on its own
+	others := math.Pow(0.85, e) * math.Pow(0.6, e)
+
+	for _, each := range []struct {
+		name     string
+		policy   TrustDomainPolicy
+		expected float64
+	}{
+		{
+			name:     "count each",
+			policy:   &CountEachPolicy{},
+			expected: 0.95 * math.Pow(0.9*math.Pow(0.8, e), e) * math.Pow(0.92*math.Pow(0.7, e), e) * math.Pow(0.94, e) * others,
+		},
+		{
+			name:     "worst member",
+			policy:   &WorstMemberPolicy{},
+			expected: 0.95 * math.Pow(0.9*awsDependencies, e) * others,
+		},
+		{
+			name:     "geometric mean",
+			policy:   &GeometricMeanPolicy{},
+			expected: 0.95 * math.Pow(math.Cbrt(0.9*0.92*0.94)*awsDependencies, e) * others,
+		},
+	} {
+		t.Run(each.name, func(t *testing.T) {
+			evaluator, err := NewEvaluator(intrinsic, deps, WithTrustDomainGrouping(each.policy))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actual, err := evaluator.trustworthiness.evaluate(context.Background(), &evaluationState{}, Package{Name: "app"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			allowedError := 1e-10
+
+			if math.Abs(actual-each.expected) > allowedError {
+				t.Fatalf("expected %g, got %g", each.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTrustDomainGroupingCancellation(t *testing.T) {
+	grouping := &trustDomainGrouping{
+		policy:       &WorstMemberPolicy{},
+		repositories: &testRepositoryResolver{},
+		cache:        make(map[Package]string),
+	}
+	deps := []evaluatedDependency{{pkg: Package{Name: "mystery"}, logIntrinsic: math.Log(0.6), logAggregated: math.Log(0.6)}}
+
+	// without cancellation, a dependency whose repository is unknown is a trust domain of its own
+	domains, err := grouping.combine(context.Background(), deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(domains) != 1 || domains[0] != math.Log(0.6) {
+		t.Errorf("expected one trust domain with log trustworthiness %g, got %v", math.Log(0.6), domains)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = grouping.combine(ctx, deps)
+	if err == nil {
+		t.Errorf("expected an error once the context is cancelled")
+	}
+}
+
+func TestTrustDomainGroupingRequiresRepositoryResolver(t *testing.T) {
+	_, err := NewEvaluator(&testIntrinsicTrustworthinessEvaluator{}, &testDependencyResolver{}, WithTrustDomainGrouping(&WorstMemberPolicy{}))
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestCombineLogMatchesCombine(t *testing.T) {
+	members := []float64{0.9, 0.5, 0.75}
+	logMembers := make([]float64, len(members))
+	for i, m := range members {
+		logMembers[i] = math.Log(m)
+	}
+
+	for _, policy := range []LogTrustDomainPolicy{
+		&CountEachPolicy{},
+		&WorstMemberPolicy{},
+		&GeometricMeanPolicy{},
+	} {
+		expected := math.Log(policy.Combine(members))
+		actual := policy.CombineLog(logMembers)
+		if math.Abs(actual-expected) > 1e-12 {
+			t.Errorf("%T: expected %f, got %f", policy, expected, actual)
+		}
+	}
+}